@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/configs"
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/pkg/postgres"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+const backfillEmailsUsage = "usage: backfill-emails [-dry-run]"
+
+// runBackfillEmailsCommand is the 'backfill-emails' subcommand of the app, args are the arguments
+// after 'backfill-emails'. It fails if there are any collisions, since the migration creating
+// the case-insensitive unique index would fail as well
+func runBackfillEmailsCommand(ctx context.Context, cfgs *configs.Configs, args []string) error {
+	fset := flag.NewFlagSet("backfill-emails", flag.ContinueOnError)
+	dryRun := fset.Bool("dry-run", false, "only report, without updating any user")
+	fset.SetOutput(io.Discard)
+
+	err := fset.Parse(args)
+	if err != nil {
+		return errors.ValidationErr(err, backfillEmailsUsage)
+	}
+
+	if cfgs.Store() != configs.StorePostgres {
+		return errors.Validationf("backfill-emails requires the %s store", configs.StorePostgres)
+	}
+
+	pqdriver, err := postgres.NewPool(cfgs.Postgres())
+	if err != nil {
+		return err
+	}
+	defer pqdriver.Close()
+
+	svc := users.NewService(users.NewPostgresStore(pqdriver, cfgs.UserPostgresTable()), nil)
+	result, err := svc.BackfillEmails(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, collision := range result.Collisions {
+		logger.Warn(ctx, fmt.Sprintf(
+			"[backfill-emails] collision on %s, users: [%s], emails: [%s]",
+			collision.Email,
+			strings.Join(collision.UserIDs, ", "),
+			strings.Join(collision.Emails, ", "),
+		))
+	}
+
+	action := "normalized"
+	if *dryRun {
+		action = "to be normalized"
+	}
+	logger.Info(ctx, fmt.Sprintf(
+		"[backfill-emails] scanned %d users, %d %s, %d collisions",
+		result.Scanned, result.Normalized, action, len(result.Collisions),
+	))
+
+	if len(result.Collisions) > 0 {
+		return errors.Validationf(
+			"%d email collisions should be resolved manually before migrating",
+			len(result.Collisions),
+		)
+	}
+
+	return nil
+}
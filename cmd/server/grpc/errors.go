@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/naughtygopher/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// errorStatus converts the error returned by a handler to a gRPC status, the same way the HTTP
+// handlers convert errors to HTTP status codes. The details of internal errors are logged, and
+// only a generic message is responded
+func errorStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// errors which are already a status, e.g. returned by the gRPC library, are returned as is
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+
+	code, msg, _ := errors.GRPCStatusCodeMessage(err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case code == codes.Unknown:
+		// errors without a type are unexpected, and are treated as internal errors
+		code = codes.Internal
+	}
+
+	if code == codes.Internal {
+		logger.Error(ctx, errors.Stacktrace(err))
+		return status.Error(code, errors.DefaultMessage)
+	}
+
+	st := status.New(code, msg)
+	ferrs := users.FieldErrors{}
+	if !errors.As(err, &ferrs) {
+		return st.Err()
+	}
+
+	// field level errors are attached as details, so clients can show them per field
+	badRequest := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(ferrs)),
+	}
+	for _, ferr := range ferrs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       protoFieldName(ferr.Field),
+			Description: ferr.Message,
+		})
+	}
+
+	detailed, derr := st.WithDetails(badRequest)
+	if derr != nil {
+		logger.Error(ctx, errors.Stacktrace(errors.Wrap(derr, "failed attaching error details")))
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// protoFieldName converts the Go field name of the domain to the respective field name in the protos,
+// i.e. FullName to full_name
+func protoFieldName(field string) string {
+	sb := strings.Builder{}
+	for idx, r := range field {
+		if unicode.IsUpper(r) {
+			if idx > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func unaryErrorInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, errorStatus(ctx, err)
+	}
+
+	return resp, nil
+}
+
+func streamErrorInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return errorStatus(ss.Context(), handler(srv, ss))
+}
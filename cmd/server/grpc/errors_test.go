@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/naughtygopher/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestErrorStatus(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"validation", errors.Validation("invalid sort"), codes.InvalidArgument, "invalid sort"},
+		{"input body", errors.InputBody("invalid cursor"), codes.InvalidArgument, "invalid cursor"},
+		{"not found", errors.NotFoundErr(users.ErrUserNotFound, "user not found"), codes.NotFound, "user not found"},
+		{"duplicate", errors.DuplicateErr(users.ErrUserEmailAlreadyExists, "a@b.com"), codes.AlreadyExists, "a@b.com"},
+		{"internal", errors.InternalErr(errors.New("connection refused"), "db down"), codes.Internal, errors.DefaultMessage},
+		{"untyped", errors.New("connection refused"), codes.Internal, errors.DefaultMessage},
+		{"cancelled", errors.Wrap(context.Canceled, "failed listing users"), codes.Canceled, ""},
+		{"status", status.Error(codes.Unavailable, "unavailable"), codes.Unavailable, "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(errorStatus(ctx, tt.err))
+			if st.Code() != tt.code {
+				t.Fatalf("expected code %s, got %s", tt.code, st.Code())
+			}
+			if tt.msg != "" && !strings.HasPrefix(st.Message(), tt.msg) {
+				t.Fatalf("expected message %q, got %q", tt.msg, st.Message())
+			}
+			// stack traces (file:line) should never be sent to clients
+			if strings.Contains(st.Message(), ".go:") {
+				t.Fatalf("expected no stack trace in the message, got %q", st.Message())
+			}
+		})
+	}
+
+	if errorStatus(ctx, nil) != nil {
+		t.Fatal("expected nil for a nil error")
+	}
+}
+
+func TestErrorStatus_FieldViolations(t *testing.T) {
+	err := (&users.User{Email: "not an email"}).ValidateForCreate()
+	st := status.Convert(errorStatus(context.Background(), err))
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected code %s, got %s", codes.InvalidArgument, st.Code())
+	}
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = br
+		}
+	}
+	if badRequest == nil {
+		t.Fatalf("expected BadRequest details, got %v", st.Details())
+	}
+
+	fields := map[string]string{}
+	for _, fv := range badRequest.GetFieldViolations() {
+		fields[fv.GetField()] = fv.GetDescription()
+	}
+	if len(fields) != 2 || fields["full_name"] == "" || fields["email"] == "" {
+		t.Fatalf("expected violations of full_name & email, got %v", fields)
+	}
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/naughtygopher/errors"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
+	"github.com/naughtygopher/goapp/internal/api"
+)
+
+// gatewayErrorHandler responds with the same status as the gRPC server, with the HTTP status
+// code of the respective gRPC code
+func gatewayErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	marshaler runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, errorStatus(ctx, err))
+}
+
+// spanNamer names the span of the request with the route of the RPC (e.g. /api/v2/users/{id}),
+// instead of the catch-all route of the gateway on the HTTP router
+func spanNamer(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		pattern, ok := runtime.HTTPPattern(r.Context())
+		if ok {
+			trace.SpanFromContext(r.Context()).SetName(pattern.String())
+		}
+		next(w, r, pathParams)
+	}
+}
+
+// NewGateway returns an HTTP handler serving the RPCs over HTTP/JSON, with the routes as per the
+// google.api.http options of the protos. The services are called in-process, without going
+// through the gRPC server. JSON fields have the same names as in the protos, e.g. full_name
+func NewGateway(apis api.Server) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithMiddlewares(spanNamer),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	// the context is used only when calling the services over a gRPC connection
+	ctx := context.Background()
+	err := goappv1.RegisterUserServiceHandlerServer(ctx, mux, &userService{apis: apis})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed registering user service on the gateway")
+	}
+
+	err = goappv1.RegisterUserNoteServiceHandlerServer(ctx, mux, &userNoteService{apis: apis})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed registering user note service on the gateway")
+	}
+
+	return mux, nil
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestGateway(t *testing.T) {
+	apis := api.NewServer(users.NewService(users.NewMemoryStore(), nil), nil, nil)
+	gateway, err := NewGateway(apis)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	serve := func(method, path, body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		gateway.ServeHTTP(rec, req)
+
+		payload := map[string]any{}
+		err := json.Unmarshal(rec.Body.Bytes(), &payload)
+		if err != nil {
+			t.Fatalf("expected a JSON response, got %q", rec.Body.String())
+		}
+		return rec.Code, payload
+	}
+
+	code, created := serve(http.MethodPost, "/api/v2/users", `{"full_name":"Jane Doe","email":"Jane@Example.com"}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, %v", http.StatusOK, code, created)
+	}
+	if created["email"] != "jane@example.com" || created["status"] != string(users.StatusPending) {
+		t.Fatalf("expected a pending user with the email normalized, got %v", created)
+	}
+
+	code, read := serve(http.MethodGet, "/api/v2/users/"+created["id"].(string), "")
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, %v", http.StatusOK, code, read)
+	}
+	if read["full_name"] != "Jane Doe" {
+		t.Fatalf("expected full_name Jane Doe, got %v", read)
+	}
+
+	code, failed := serve(http.MethodPost, "/api/v2/users", `{"email":"jane@example.com"}`)
+	if code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d, %v", http.StatusBadRequest, code, failed)
+	}
+	if details, _ := failed["details"].([]any); len(details) == 0 {
+		t.Fatalf("expected field violations in the details, got %v", failed)
+	}
+
+	code, failed = serve(http.MethodGet, "/api/v2/users/unknown", "")
+	if code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d, %v", http.StatusNotFound, code, failed)
+	}
+}
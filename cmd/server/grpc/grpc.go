@@ -1,22 +1,112 @@
+// Package grpc serves the APIs over gRPC, the service definitions are in schemas/proto. It has the
+// same semantics as the HTTP APIs, and is preferred by internal services
 package grpc
 
 import (
 	"context"
+	"fmt"
+	"net"
 
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/proberesponder"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
 	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/pkg/apm"
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
 )
 
+// Config holds all the configuration required to start the gRPC server
+type Config struct {
+	Host string
+	Port uint16
+	// Reflection enables the gRPC server reflection service, which lets clients like grpcurl
+	// discover the services without the protos
+	Reflection bool
+}
+
 type GRPC struct {
-	apis api.Server
+	apis     api.Server
+	listener string
+	server   *grpc.Server
+	health   *health.Server
 }
 
-func (gr *GRPC) Shutdown(ctx context.Context) error {
-	_ = ctx
+// Start listens on the configured port and serves the requests. It blocks until the server is
+// stopped, and returns nil if stopped using Shutdown
+func (gr *GRPC) Start() error {
+	lis, err := net.Listen("tcp", gr.listener)
+	if err != nil {
+		return errors.Wrapf(err, "failed listening on %s", gr.listener)
+	}
+
+	logger.Info(context.Background(), fmt.Sprintf("[grpc] listening on %s", gr.listener))
+	err = gr.server.Serve(lis)
+	if err != nil {
+		return errors.Wrap(err, "failed serving gRPC")
+	}
+
 	return nil
 }
 
-func New(apis api.Server) *GRPC {
+// Shutdown stops accepting new requests and waits for the requests in progress to complete. If
+// the context is done before that, all the requests in progress are cancelled
+func (gr *GRPC) Shutdown(ctx context.Context) error {
+	// health checks respond as not serving while the requests in progress are being completed
+	gr.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		gr.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		gr.server.Stop()
+		return errors.Wrap(ctx.Err(), "failed shutting down gRPC server gracefully")
+	}
+}
+
+// New returns an instance of GRPC with all the services registered. The health service responds
+// based on the readiness of probes
+func New(cfg *Config, apis api.Server, probes *proberesponder.ProbeResponder) *GRPC {
+	server := grpc.NewServer(
+		// health checks & reflection are not traced, same as the /-/ routes of HTTP
+		grpc.StatsHandler(apm.OtelGRPCNewServerHandler(
+			healthpb.Health_ServiceDesc.ServiceName,
+			reflectionpb.ServerReflection_ServiceDesc.ServiceName,
+			reflectionalphapb.ServerReflection_ServiceDesc.ServiceName,
+		)),
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor),
+	)
+	goappv1.RegisterUserServiceServer(server, &userService{apis: apis})
+	goappv1.RegisterUserNoteServiceServer(server, &userNoteService{apis: apis})
+
+	hs := newHealthServer(
+		probes,
+		goappv1.UserService_ServiceDesc.ServiceName,
+		goappv1.UserNoteService_ServiceDesc.ServiceName,
+	)
+	healthpb.RegisterHealthServer(server, hs)
+
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
+
 	return &GRPC{
-		apis: apis,
+		apis:     apis,
+		listener: net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port)),
+		server:   server,
+		health:   hs,
 	}
 }
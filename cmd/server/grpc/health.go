@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"github.com/naughtygopher/proberesponder"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// newHealthServer returns the standard grpc.health.v1 service, with the serving status of the
+// server as well as of each of the services, driven by the readiness of probes. So the gRPC
+// clients & load balancers stop sending requests exactly when the HTTP readiness probe fails
+func newHealthServer(probes *proberesponder.ProbeResponder, services ...string) *health.Server {
+	hs := health.NewServer()
+	services = append(services, "")
+
+	setStatus := func() {
+		status := healthpb.HealthCheckResponse_SERVING
+		if probes.NotStarted() || probes.NotReady() {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		for _, svc := range services {
+			hs.SetServingStatus(svc, status)
+		}
+	}
+
+	setStatus()
+	if probes != nil {
+		probes.SetListener(func(proberesponder.Statuskey, bool) {
+			setStatus()
+		})
+	}
+
+	return hs
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naughtygopher/proberesponder"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	probes := proberesponder.New()
+	gr := New(&Config{}, nil, probes)
+
+	assertStatus := func(service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := gr.health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.GetStatus() != expected {
+			t.Fatalf("expected %s for %q, got %s", expected, service, resp.GetStatus())
+		}
+	}
+
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	probes.SetNotStarted(false)
+	probes.SetNotReady(false)
+	assertStatus("", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("goapp.v1.UserService", healthpb.HealthCheckResponse_SERVING)
+
+	probes.SetNotReady(true)
+	assertStatus("goapp.v1.UserNoteService", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	probes.SetNotReady(false)
+	err := gr.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+}
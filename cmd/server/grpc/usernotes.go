@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// userNoteService implements goappv1.UserNoteServiceServer
+type userNoteService struct {
+	goappv1.UnimplementedUserNoteServiceServer
+	apis api.Server
+}
+
+func noteToProto(note *usernotes.Note) *goappv1.Note {
+	pnote := &goappv1.Note{
+		Id:        note.ID,
+		Title:     note.Title,
+		Content:   note.Content,
+		CreatedAt: timestamppb.New(note.CreatedAt),
+		UpdatedAt: timestamppb.New(note.UpdatedAt),
+	}
+	if note.Creator != nil {
+		pnote.UserId = note.Creator.ID
+	}
+
+	return pnote
+}
+
+func (uns *userNoteService) CreateNote(ctx context.Context, req *goappv1.CreateNoteRequest) (*goappv1.Note, error) {
+	note, err := uns.apis.CreateUserNote(ctx, &usernotes.Note{
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Creator: &users.User{ID: req.GetUserId()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return noteToProto(note), nil
+}
+
+func (uns *userNoteService) GetNote(ctx context.Context, req *goappv1.GetNoteRequest) (*goappv1.Note, error) {
+	note, err := uns.apis.ReadUserNote(ctx, req.GetUserId(), req.GetNoteId())
+	if err != nil {
+		return nil, err
+	}
+
+	return noteToProto(note), nil
+}
+
+func (uns *userNoteService) ListNotes(ctx context.Context, req *goappv1.ListNotesRequest) (*goappv1.ListNotesResponse, error) {
+	list, err := uns.apis.ListUserNotes(
+		ctx,
+		req.GetUserId(),
+		req.GetCursor(),
+		int(req.GetLimit()),
+		usernotes.SortOrder(req.GetSort()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &goappv1.ListNotesResponse{
+		Notes:      make([]*goappv1.Note, 0, len(list.Notes)),
+		NextCursor: list.NextCursor,
+	}
+	for idx := range list.Notes {
+		resp.Notes = append(resp.Notes, noteToProto(&list.Notes[idx]))
+	}
+
+	return resp, nil
+}
+
+func (uns *userNoteService) UpdateNote(ctx context.Context, req *goappv1.UpdateNoteRequest) (*goappv1.Note, error) {
+	note, err := uns.apis.UpdateUserNote(
+		ctx,
+		req.GetUserId(),
+		req.GetNoteId(),
+		&usernotes.NoteUpdate{
+			Title:   req.Title,
+			Content: req.Content,
+		},
+		timeFromProto(req.GetVersion()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return noteToProto(note), nil
+}
+
+func (uns *userNoteService) DeleteNote(ctx context.Context, req *goappv1.DeleteNoteRequest) (*emptypb.Empty, error) {
+	err := uns.apis.DeleteUserNote(ctx, req.GetUserId(), req.GetNoteId(), timeFromProto(req.GetVersion()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// userService implements goappv1.UserServiceServer
+type userService struct {
+	goappv1.UnimplementedUserServiceServer
+	apis api.Server
+}
+
+func userToProto(u *users.User) *goappv1.User {
+	return &goappv1.User{
+		Id:             u.ID,
+		FullName:       u.FullName,
+		Email:          u.Email,
+		Phone:          u.Phone,
+		ContactAddress: u.ContactAddress,
+		Status:         string(u.Status),
+		CreatedAt:      timestamppb.New(u.CreatedAt),
+		UpdatedAt:      timestamppb.New(u.UpdatedAt),
+	}
+}
+
+// timeFromProto returns the zero time for a nil timestamp, unlike AsTime which returns the Unix epoch
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func (us *userService) CreateUser(ctx context.Context, req *goappv1.CreateUserRequest) (*goappv1.User, error) {
+	u, err := us.apis.CreateUser(ctx, &users.User{
+		FullName:       req.GetFullName(),
+		Email:          req.GetEmail(),
+		Phone:          req.GetPhone(),
+		ContactAddress: req.GetContactAddress(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(u), nil
+}
+
+func (us *userService) GetUser(ctx context.Context, req *goappv1.GetUserRequest) (*goappv1.User, error) {
+	u, err := us.apis.ReadUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(u), nil
+}
+
+func (us *userService) GetUserByEmail(ctx context.Context, req *goappv1.GetUserByEmailRequest) (*goappv1.User, error) {
+	u, err := us.apis.ReadUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(u), nil
+}
+
+func (us *userService) UpdateUser(ctx context.Context, req *goappv1.UpdateUserRequest) (*goappv1.User, error) {
+	u, err := us.apis.UpdateUser(ctx, req.GetId(), &users.UserUpdate{
+		FullName:       req.FullName,
+		Email:          req.Email,
+		Phone:          req.Phone,
+		ContactAddress: req.ContactAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(u), nil
+}
+
+func (us *userService) DeleteUser(ctx context.Context, req *goappv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	err := us.apis.DeleteUser(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (us *userService) ListUsers(ctx context.Context, req *goappv1.ListUsersRequest) (*goappv1.ListUsersResponse, error) {
+	list, err := us.apis.ListUsers(ctx, &users.ListOptions{
+		Filter: users.ListFilter{
+			EmailDomain:   req.GetEmailDomain(),
+			NamePrefix:    req.GetNamePrefix(),
+			CreatedAfter:  timeFromProto(req.GetCreatedAfter()),
+			CreatedBefore: timeFromProto(req.GetCreatedBefore()),
+		},
+		Sort:   users.SortOrder(req.GetSort()),
+		Limit:  int(req.GetLimit()),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &goappv1.ListUsersResponse{
+		Users:      make([]*goappv1.User, 0, len(list.Users)),
+		NextCursor: list.NextCursor,
+	}
+	for idx := range list.Users {
+		resp.Users = append(resp.Users, userToProto(&list.Users[idx]))
+	}
+
+	return resp, nil
+}
+
+func (us *userService) SendUserVerification(
+	ctx context.Context,
+	req *goappv1.SendUserVerificationRequest,
+) (*goappv1.SendUserVerificationResponse, error) {
+	expiresAt, err := us.apis.SendUserVerification(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &goappv1.SendUserVerificationResponse{ExpiresAt: timestamppb.New(expiresAt)}, nil
+}
+
+func (us *userService) VerifyUser(ctx context.Context, req *goappv1.VerifyUserRequest) (*goappv1.User, error) {
+	u, err := us.apis.VerifyUser(ctx, req.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(u), nil
+}
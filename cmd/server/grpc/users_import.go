@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"io"
+
+	"github.com/naughtygopher/errors"
+	"google.golang.org/grpc"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+const (
+	importBatchSize = 500
+	// importMaxErrors is the maximum number of errors in the response of an import, to keep the
+	// response within the message size limit of the clients
+	importMaxErrors = 1000
+)
+
+// ImportUsers receives the users in batches of importBatchSize. Messages are not received while
+// a batch is being saved, so once the flow control window of the stream is full, Send of the
+// client blocks until the database catches up. Batches saved before a failure remain saved
+func (us *userService) ImportUsers(
+	stream grpc.ClientStreamingServer[goappv1.ImportUsersRequest, goappv1.ImportUsersResponse],
+) error {
+	resp := &goappv1.ImportUsersResponse{
+		Errors: make([]*goappv1.ImportUserError, 0),
+	}
+
+	batch := make([]users.User, 0, importBatchSize)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "failed receiving users")
+		}
+
+		batch = append(batch, users.User{
+			ID:             req.GetId(),
+			FullName:       req.GetFullName(),
+			Email:          req.GetEmail(),
+			Phone:          req.GetPhone(),
+			ContactAddress: req.GetContactAddress(),
+		})
+		if len(batch) < importBatchSize {
+			continue
+		}
+
+		err = us.importBatch(stream, resp, batch)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+
+	err := us.importBatch(stream, resp, batch)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+func (us *userService) importBatch(
+	stream grpc.ClientStreamingServer[goappv1.ImportUsersRequest, goappv1.ImportUsersResponse],
+	resp *goappv1.ImportUsersResponse,
+	batch []users.User,
+) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	rowResults, err := us.apis.BulkCreateUsers(stream.Context(), batch)
+	if err != nil {
+		return err
+	}
+
+	// index of the first user of the batch in the stream
+	offset := resp.Accepted
+	resp.Accepted += int32(len(batch))
+	for _, rres := range rowResults {
+		if rres.Status == users.RowInserted {
+			resp.Inserted++
+			continue
+		}
+
+		resp.Failed++
+		if len(resp.Errors) >= importMaxErrors {
+			continue
+		}
+		resp.Errors = append(resp.Errors, &goappv1.ImportUserError{
+			Index:  offset + int32(rres.Row),
+			Id:     rres.ID,
+			Email:  rres.Email,
+			Status: string(rres.Status),
+			Error:  rres.Error,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc/goappv1"
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestImportUsers(t *testing.T) {
+	ctx := context.Background()
+	apis := api.NewServer(users.NewService(users.NewMemoryStore(), nil), nil, nil)
+	gr := New(&Config{}, apis, nil)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = gr.server.Serve(lis)
+	}()
+	defer gr.server.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	stream, err := goappv1.NewUserServiceClient(conn).ImportUsers(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// more than a batch, so that the indexes of the errors span across batches
+	total := importBatchSize + 10
+	invalid := importBatchSize + 2
+	duplicate := importBatchSize + 5
+	for idx := 0; idx < total; idx++ {
+		req := &goappv1.ImportUsersRequest{
+			FullName: fmt.Sprintf("User %d", idx),
+			Email:    fmt.Sprintf("user%d@example.com", idx),
+		}
+		switch idx {
+		case invalid:
+			req.Email = "not an email"
+		case duplicate:
+			req.Email = "User0@example.com"
+		}
+
+		err = stream.Send(req)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resp.GetAccepted() != int32(total) || resp.GetInserted() != int32(total-2) || resp.GetFailed() != 2 {
+		t.Fatalf(
+			"expected accepted %d, inserted %d & failed 2, got %d, %d & %d",
+			total, total-2, resp.GetAccepted(), resp.GetInserted(), resp.GetFailed(),
+		)
+	}
+
+	errs := resp.GetErrors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(errs))
+	}
+	if errs[0].GetIndex() != int32(invalid) || errs[0].GetStatus() != string(users.RowInvalid) {
+		t.Errorf("expected user %d to be invalid, got %v", invalid, errs[0])
+	}
+	if errs[1].GetIndex() != int32(duplicate) || errs[1].GetStatus() != string(users.RowDuplicate) {
+		t.Errorf("expected user %d to be duplicate, got %v", duplicate, errs[1])
+	}
+}
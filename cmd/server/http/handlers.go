@@ -6,18 +6,32 @@ import (
 	"html/template"
 	"net/http"
 	"runtime/debug"
+	"strings"
 
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/webgo/v7"
 
 	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/pkg/idempotency"
 	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/users"
 )
 
 // Handlers struct has all the dependencies required for HTTP handlers
 type Handlers struct {
-	apis api.Server
-	home *template.Template
+	apis        api.Server
+	home        *template.Template
+	idempotency *idempotency.Idempotency
+	// gateway serves the gRPC services over HTTP/JSON
+	gateway http.Handler
+}
+
+// idempotent makes the handler idempotent for the requests with the Idempotency-Key header,
+// i.e. retries are responded with the response of the first request
+func (h *Handlers) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.idempotency.Middleware(w, r, next)
+	}
 }
 
 func (h *Handlers) routes() []*webgo.Route {
@@ -33,19 +47,135 @@ func (h *Handlers) routes() []*webgo.Route {
 			Name:          "create-user",
 			Pattern:       "/users",
 			Method:        http.MethodPost,
-			Handlers:      []http.HandlerFunc{errWrapper(h.CreateUser)},
+			Handlers:      []http.HandlerFunc{h.idempotent(errWrapper(h.CreateUser))},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "list-users",
+			Pattern:       "/users",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ListUsers)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "import-users",
+			Pattern:       "/users/import",
+			Method:        http.MethodPost,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ImportUsers)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "export-users",
+			Pattern:       "/users/export",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ExportUsers)},
 			TrailingSlash: true,
 		},
 		{
 			Name:          "read-user-byemail",
-			Pattern:       "/users/:email",
+			Pattern:       "/users/email/:email",
 			Method:        http.MethodGet,
 			Handlers:      []http.HandlerFunc{errWrapper(h.ReadUserByEmail)},
 			TrailingSlash: true,
 		},
+		{
+			Name:          "read-user",
+			Pattern:       "/users/:id",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ReadUserByID)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "update-user",
+			Pattern:       "/users/:id",
+			Method:        http.MethodPatch,
+			Handlers:      []http.HandlerFunc{errWrapper(h.UpdateUser)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "delete-user",
+			Pattern:       "/users/:id",
+			Method:        http.MethodDelete,
+			Handlers:      []http.HandlerFunc{errWrapper(h.DeleteUser)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "send-user-verification",
+			Pattern:       "/users/:id/verification",
+			Method:        http.MethodPost,
+			Handlers:      []http.HandlerFunc{errWrapper(h.SendUserVerification)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "verify-user",
+			Pattern:       "/verify",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.VerifyUser)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "create-user-note",
+			Pattern:       "/users/:userID/notes",
+			Method:        http.MethodPost,
+			Handlers:      []http.HandlerFunc{h.idempotent(errWrapper(h.CreateUserNote))},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "list-user-notes",
+			Pattern:       "/users/:userID/notes",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ListUserNotes)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "read-user-note",
+			Pattern:       "/users/:userID/notes/:noteID",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ReadUserNote)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "update-user-note",
+			Pattern:       "/users/:userID/notes/:noteID",
+			Method:        http.MethodPatch,
+			Handlers:      []http.HandlerFunc{errWrapper(h.UpdateUserNote)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "delete-user-note",
+			Pattern:       "/users/:userID/notes/:noteID",
+			Method:        http.MethodDelete,
+			Handlers:      []http.HandlerFunc{errWrapper(h.DeleteUserNote)},
+			TrailingSlash: true,
+		},
+		{
+			Name:          "read-job",
+			Pattern:       "/jobs/:id",
+			Method:        http.MethodGet,
+			Handlers:      []http.HandlerFunc{errWrapper(h.ReadJob)},
+			TrailingSlash: true,
+		},
 	}
 }
 
+// gatewayRoutes are the catch-all routes of /api/v2, the gateway routes them to the respective
+// RPCs. Being routes of the same router, they have the same middleware as the rest
+func (h *Handlers) gatewayRoutes() []*webgo.Route {
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete}
+	routes := make([]*webgo.Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, &webgo.Route{
+			Name:          "api-v2-" + strings.ToLower(method),
+			Pattern:       "/api/v2/:path*",
+			Method:        method,
+			Handlers:      []http.HandlerFunc{h.gateway.ServeHTTP},
+			TrailingSlash: true,
+		})
+	}
+
+	return routes
+}
+
 func (h *Handlers) HelloWorld(w http.ResponseWriter, r *http.Request) error {
 	contentType := r.Header.Get("Content-Type")
 	switch contentType {
@@ -85,20 +215,40 @@ func errWrapper(h func(w http.ResponseWriter, r *http.Request) error) http.Handl
 			return
 		}
 
-		status, msg, _ := errors.HTTPStatusCodeMessage(err)
-		webgo.SendError(w, msg, status)
+		status, msg := httpStatus(err)
+		ferrs := users.FieldErrors{}
+		if errors.As(err, &ferrs) {
+			// field level errors are responded along with the message, so clients can show them per field
+			webgo.SendError(w, map[string]any{"message": msg, "fields": ferrs}, status)
+		} else {
+			webgo.SendError(w, msg, status)
+		}
 		if status > 499 {
 			logger.Error(r.Context(), errors.Stacktrace(err))
 		}
 	}
 }
 
+// httpStatus returns the HTTP status code and message to respond with for the error
+func httpStatus(err error) (int, string) {
+	status, msg, _ := errors.HTTPStatusCodeMessage(err)
+	if errors.Is(err, errWeakETag) {
+		status = http.StatusPreconditionFailed
+	}
+
+	return status, msg
+}
+
 func panicRecoverer(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	defer func() {
 		p := recover()
 		if p == nil {
 			return
 		}
+		if p == http.ErrAbortHandler {
+			// the handler has aborted the response intentionally, e.g. an export which failed midway
+			panic(p)
+		}
 		webgo.R500(w, errors.DefaultMessage)
 
 		logger.Error(r.Context(), fmt.Sprintf("%+v", p))
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/naughtygopher/webgo/v7"
+)
+
+// ReadJob is the HTTP handler to read the status & result of a background job
+func (h *Handlers) ReadJob(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	jobID := wctx.Params()["id"]
+
+	job, err := h.apis.ReadJob(r.Context(), jobID)
+	if err != nil {
+		return err
+	}
+
+	// payload is not exposed since it can be large, and has data already known to the caller
+	job.Payload = nil
+	webgo.R200(w, job)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/naughtygopher/webgo/v7"
+
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/pkg/mailer"
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
+	"github.com/naughtygopher/goapp/internal/verification"
+)
+
+// newTestRouter returns a router with all the routes (except of the gateway), backed by in-memory stores
+func newTestRouter() *webgo.Router {
+	us := users.NewService(users.NewMemoryStore(), nil)
+	vr := verification.NewService(
+		verification.NewMemoryStore(),
+		us,
+		mailer.NewLog("goapp@example.com"),
+		verification.Options{Secret: "test-secret"},
+	)
+	h := &Handlers{apis: api.New(us, usernotes.NewService(usernotes.NewMemoryStore()), vr)}
+
+	return webgo.NewRouter(&webgo.Config{}, h.routes()...)
+}
+
+func TestRoutes_ImportUsers(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/users/import",
+		strings.NewReader(`{"FullName":"Jane Doe","Email":"jane@example.com"}`+"\n"),
+	)
+	req.Header.Set(webgo.HeaderContentType, contentTypeNDJSON)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("X-Import-Inserted") != "1" {
+		t.Fatalf("expected 1 user imported, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// the paths which are not routes must not be caught by the import
+	for _, path := range []string{"/users:import", "/import", "/verify"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader("")))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("POST %s: expected 404, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestRoutes_ExportUsers(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/export?format=csv", nil))
+	if w.Code != http.StatusOK || w.Header().Get(webgo.HeaderContentType) != users.ExportCSV.ContentType() {
+		t.Fatalf("expected a CSV export, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// the verification link must reach its own route, and not be caught by the export
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/verify?token=x", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected GET /verify to reject the invalid token with 422, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users:export", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /users:export: expected 404, got %d", w.Code)
+	}
+}
@@ -2,26 +2,213 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
 	"github.com/naughtygopher/webgo/v7"
 )
 
+// CreateUserNote is the HTTP handler to create a new note for the user in the URI
 func (h *Handlers) CreateUserNote(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["userID"]
+
 	unote := new(usernotes.Note)
 	err := json.NewDecoder(r.Body).Decode(unote)
 	if err != nil {
 		return errors.InputBodyErr(err, "invalid JSON provided")
 	}
+	// the creator is always the user in the URI, irrespective of what's in the payload
+	unote.Creator = &users.User{ID: userID}
 
 	un, err := h.apis.CreateUserNote(r.Context(), unote)
 	if err != nil {
 		return err
 	}
 
+	setNoteETag(w, un)
+	webgo.R200(w, un)
+
+	return nil
+}
+
+// ReadUserNote is the HTTP handler to read a single note of a user
+func (h *Handlers) ReadUserNote(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	params := wctx.Params()
+
+	un, err := h.apis.ReadUserNote(r.Context(), params["userID"], params["noteID"])
+	if err != nil {
+		return err
+	}
+
+	setNoteETag(w, un)
+	webgo.R200(w, un)
+
+	return nil
+}
+
+// ListUserNotes is the HTTP handler to list notes of a user, one page at a time
+func (h *Handlers) ListUserNotes(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["userID"]
+	query := r.URL.Query()
+
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			return errors.InputBodyErr(err, "invalid limit provided")
+		}
+	}
+
+	list, err := h.apis.ListUserNotes(
+		r.Context(),
+		userID,
+		query.Get("cursor"),
+		limit,
+		usernotes.SortOrder(query.Get("sort")),
+	)
+	if err != nil {
+		return err
+	}
+
+	webgo.R200(w, list)
+
+	return nil
+}
+
+// UpdateUserNote is the HTTP handler to partially update a note of a user. If the 'If-Match'
+// header is provided, the update fails with 409 if the note was modified since
+func (h *Handlers) UpdateUserNote(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	params := wctx.Params()
+
+	version, err := h.ifMatchVersion(r, params["userID"], params["noteID"])
+	if err != nil {
+		return err
+	}
+
+	nu := new(usernotes.NoteUpdate)
+	err = json.NewDecoder(r.Body).Decode(nu)
+	if err != nil {
+		return errors.InputBodyErr(err, "invalid JSON provided")
+	}
+
+	un, err := h.apis.UpdateUserNote(r.Context(), params["userID"], params["noteID"], nu, version)
+	if err != nil {
+		return err
+	}
+
+	setNoteETag(w, un)
 	webgo.R200(w, un)
 
 	return nil
 }
+
+// DeleteUserNote is the HTTP handler to delete a note of a user. If the 'If-Match'
+// header is provided, the deletion fails with 409 if the note was modified since
+func (h *Handlers) DeleteUserNote(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	params := wctx.Params()
+
+	version, err := h.ifMatchVersion(r, params["userID"], params["noteID"])
+	if err != nil {
+		return err
+	}
+
+	err = h.apis.DeleteUserNote(r.Context(), params["userID"], params["noteID"], version)
+	if err != nil {
+		return err
+	}
+
+	webgo.R204(w)
+
+	return nil
+}
+
+// errWeakETag is responded with 412, since If-Match requires a strong comparison (RFC 9110) and
+// a weak ETag never matches
+var errWeakETag = errors.New("weak ETags do not match for If-Match")
+
+func setNoteETag(w http.ResponseWriter, note *usernotes.Note) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", note.Version()))
+}
+
+// ifMatchVersions returns the note versions in the 'If-Match' header, which can be a comma
+// separated list of ETags. No versions are returned if the header is not provided or is '*',
+// i.e. any version. Weak ETags are skipped, and errWeakETag is returned if all of them are weak
+func ifMatchVersions(r *http.Request) ([]time.Time, error) {
+	header := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if header == "" {
+		return nil, nil
+	}
+
+	weak := 0
+	versions := make([]time.Time, 0, 1)
+	for _, etag := range strings.Split(header, ",") {
+		etag = strings.TrimSpace(etag)
+		if etag == "" {
+			continue
+		}
+		if etag == "*" {
+			return nil, nil
+		}
+
+		// the ETags of notes are always strong, so a weak ETag can not match any version
+		if strings.HasPrefix(etag, "W/") {
+			weak++
+			continue
+		}
+
+		version, err := usernotes.ParseVersion(strings.Trim(etag, `"`))
+		if err != nil {
+			return nil, errors.InputBodyErr(err, "invalid If-Match header")
+		}
+		versions = append(versions, version)
+	}
+
+	if len(versions) == 0 && weak > 0 {
+		return nil, errors.InputBodyErr(errWeakETag, "If-Match requires a strong ETag")
+	}
+
+	return versions, nil
+}
+
+// ifMatchVersion returns the note version to be matched for a conditional update or delete.
+// A zero time is returned if any version is acceptable. When there are multiple ETags, the
+// version of the note is returned if it matches any of them, and the datastore still makes sure
+// it's not modified in between. If none of them match, the first is returned which fails with 409
+func (h *Handlers) ifMatchVersion(r *http.Request, userID string, noteID string) (time.Time, error) {
+	versions, err := ifMatchVersions(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch len(versions) {
+	case 0:
+		return time.Time{}, nil
+	case 1:
+		return versions[0], nil
+	}
+
+	note, err := h.apis.ReadUserNote(r.Context(), userID, noteID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, version := range versions {
+		if note.UpdatedAt.Equal(version) {
+			return version, nil
+		}
+	}
+
+	return versions[0], nil
+}
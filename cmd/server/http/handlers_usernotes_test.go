@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	v1 := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	v2 := v1.Add(time.Second)
+	etag1 := (&usernotes.Note{UpdatedAt: v1}).Version()
+	etag2 := (&usernotes.Note{UpdatedAt: v2}).Version()
+
+	tests := []struct {
+		name     string
+		headers  []string
+		expected []time.Time
+		// status is the HTTP status of the error expected
+		status int
+	}{
+		{name: "absent"},
+		{name: "any", headers: []string{"*"}},
+		{name: "strong", headers: []string{`"` + etag1 + `"`}, expected: []time.Time{v1}},
+		{name: "weak", headers: []string{`W/"` + etag1 + `"`}, status: http.StatusPreconditionFailed},
+		{name: "list", headers: []string{`"` + etag1 + `", "` + etag2 + `"`}, expected: []time.Time{v1, v2}},
+		{name: "list with weak", headers: []string{`"` + etag1 + `", W/"` + etag2 + `"`}, expected: []time.Time{v1}},
+		{name: "multiple headers", headers: []string{`"` + etag1 + `"`, `"` + etag2 + `"`}, expected: []time.Time{v1, v2}},
+		{name: "list with any", headers: []string{`"` + etag1 + `", *`}},
+		{name: "malformed", headers: []string{`"yesterday"`}, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			for _, h := range tt.headers {
+				r.Header.Add("If-Match", h)
+			}
+
+			got, err := ifMatchVersions(r)
+			if tt.status != 0 {
+				status, _ := httpStatus(err)
+				if status != tt.status {
+					t.Fatalf("expected status %d, got %d (%v)", tt.status, status, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for idx := range got {
+				if !got[idx].Equal(tt.expected[idx]) {
+					t.Fatalf("expected %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlers_IfMatchVersion(t *testing.T) {
+	ctx := context.Background()
+	un := usernotes.NewService(usernotes.NewMemoryStore())
+	h := &Handlers{apis: api.New(nil, un, nil)}
+
+	note, err := un.SaveNote(ctx, &usernotes.Note{
+		Title:   "title",
+		Content: "content",
+		Creator: &users.User{ID: "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the ETag emitted is accepted as is in If-Match
+	w := httptest.NewRecorder()
+	setNoteETag(w, note)
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected the ETag header to be set")
+	}
+
+	stale := `"` + (&usernotes.Note{UpdatedAt: note.UpdatedAt.Add(-time.Second)}).Version() + `"`
+	tests := []struct {
+		name     string
+		ifMatch  string
+		expected time.Time
+	}{
+		{name: "current", ifMatch: etag, expected: note.UpdatedAt},
+		{name: "stale", ifMatch: stale, expected: note.UpdatedAt.Add(-time.Second)},
+		{name: "list with current", ifMatch: stale + ", " + etag, expected: note.UpdatedAt},
+		// none of them match, so the first is used which fails with a conflict
+		{name: "list without current", ifMatch: stale + `, "1"`, expected: note.UpdatedAt.Add(-time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			r.Header.Set("If-Match", tt.ifMatch)
+
+			got, err := h.ifMatchVersion(r, note.Creator.ID, note.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	// a stale version fails with 409
+	title := "updated"
+	r := httptest.NewRequest(http.MethodPatch, "/", nil)
+	r.Header.Set("If-Match", stale)
+	version, err := h.ifMatchVersion(r, note.Creator.ID, note.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = h.apis.UpdateUserNote(ctx, note.Creator.ID, note.ID, &usernotes.NoteUpdate{Title: &title}, version)
+	status, _, _ := errors.HTTPStatusCodeMessage(err)
+	if status != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d (%v)", http.StatusConflict, status, err)
+	}
+}
+
+func TestRoutes_CreateUserNote_Unverified(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(
+		http.MethodPost,
+		"/users",
+		strings.NewReader(`{"FullName":"Jane Doe","Email":"jane@example.com"}`),
+	))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the user to be created, got %d: %s", w.Code, w.Body.String())
+	}
+	created := struct {
+		Data users.User `json:"data"`
+	}{}
+	err := json.Unmarshal(w.Body.Bytes(), &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unverified user is known, but not permitted to create notes
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(
+		http.MethodPost,
+		"/users/"+created.Data.ID+"/notes",
+		strings.NewReader(`{"Title":"title","Content":"content"}`),
+	))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for an unverified user, got %d: %s", w.Code, w.Body.String())
+	}
+}
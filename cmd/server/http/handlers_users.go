@@ -2,7 +2,12 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/webgo/v7"
@@ -32,8 +37,10 @@ func (h *Handlers) CreateUser(w http.ResponseWriter, r *http.Request) error {
 // ReadUserByEmail is the HTTP handler to read an existing user by email
 func (h *Handlers) ReadUserByEmail(w http.ResponseWriter, r *http.Request) error {
 	wctx := webgo.Context(r)
-	email := wctx.Params()["email"]
+	return h.readUserByEmail(w, r, wctx.Params()["email"])
+}
 
+func (h *Handlers) readUserByEmail(w http.ResponseWriter, r *http.Request, email string) error {
 	out, err := h.apis.ReadUserByEmail(r.Context(), email)
 	if err != nil {
 		return err
@@ -43,3 +50,114 @@ func (h *Handlers) ReadUserByEmail(w http.ResponseWriter, r *http.Request) error
 
 	return nil
 }
+
+// ReadUserByID is the HTTP handler to read an existing user by ID. User IDs never have an '@',
+// so for the clients of the deprecated GET /users/:email, the user is read by email instead
+func (h *Handlers) ReadUserByID(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["id"]
+	if strings.Contains(userID, "@") {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("</users/email/%s>; rel=\"successor-version\"", url.PathEscape(userID)))
+		return h.readUserByEmail(w, r, userID)
+	}
+
+	out, err := h.apis.ReadUserByID(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	webgo.R200(w, out)
+
+	return nil
+}
+
+// UpdateUser is the HTTP handler to partially update an existing user. Only the fields
+// present in the JSON payload are updated
+func (h *Handlers) UpdateUser(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["id"]
+
+	uu := new(users.UserUpdate)
+	err := json.NewDecoder(r.Body).Decode(uu)
+	if err != nil {
+		return errors.InputBodyErr(err, "invalid JSON provided")
+	}
+
+	out, err := h.apis.UpdateUser(r.Context(), userID, uu)
+	if err != nil {
+		return err
+	}
+
+	webgo.R200(w, out)
+
+	return nil
+}
+
+// DeleteUser is the HTTP handler to delete an existing user
+func (h *Handlers) DeleteUser(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["id"]
+
+	err := h.apis.DeleteUser(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	webgo.R204(w)
+
+	return nil
+}
+
+// ListUsers is the HTTP handler to list users, one page at a time. All the filters
+// as well as the pagination options are read from the query string
+func (h *Handlers) ListUsers(w http.ResponseWriter, r *http.Request) error {
+	opts, err := userListOptions(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	out, err := h.apis.ListUsers(r.Context(), opts)
+	if err != nil {
+		return err
+	}
+
+	webgo.R200(w, out)
+
+	return nil
+}
+
+func userListOptions(query url.Values) (*users.ListOptions, error) {
+	opts := &users.ListOptions{
+		Cursor: query.Get("cursor"),
+		Sort:   users.SortOrder(query.Get("sort")),
+		Filter: users.ListFilter{
+			EmailDomain: query.Get("email_domain"),
+			NamePrefix:  query.Get("name_prefix"),
+		},
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		opts.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return nil, errors.InputBodyErr(err, "invalid limit provided")
+		}
+	}
+
+	if after := query.Get("created_after"); after != "" {
+		opts.Filter.CreatedAfter, err = time.Parse(time.RFC3339, after)
+		if err != nil {
+			return nil, errors.InputBodyErr(err, "invalid created_after provided, expected RFC3339")
+		}
+	}
+
+	if before := query.Get("created_before"); before != "" {
+		opts.Filter.CreatedBefore, err = time.Parse(time.RFC3339, before)
+		if err != nil {
+			return nil, errors.InputBodyErr(err, "invalid created_before provided, expected RFC3339")
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/webgo/v7"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+const (
+	// exportFlushSize is the number of users written before flushing the response
+	exportFlushSize = 1000
+	// exportFlushTimeout is the time allowed to write a single flush of users. The server's
+	// write timeout applies to the whole response, which would limit the size of an export
+	exportFlushTimeout = time.Minute
+)
+
+// exportFormat returns the format from the query param 'format', else from the Accept header.
+// NDJSON is the default
+func exportFormat(r *http.Request) users.ExportFormat {
+	format := users.ExportFormat(r.URL.Query().Get("format"))
+	if format != "" {
+		return format
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), users.ExportCSV.ContentType()) {
+		return users.ExportCSV
+	}
+
+	return users.ExportNDJSON
+}
+
+// ExportUsers is the HTTP handler to export all the users matching the filters as NDJSON
+// or CSV. It supports the same query params as ListUsers (except limit), and the response
+// is streamed. If the export fails midway, the response is aborted so that the client does
+// not mistake a partial export for a complete one
+func (h *Handlers) ExportUsers(w http.ResponseWriter, r *http.Request) error {
+	opts, err := userListOptions(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	format := exportFormat(r)
+	writer, err := users.NewExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	rc := http.NewResponseController(webgo.OriginalResponseWriter(w))
+	_ = rc.SetWriteDeadline(time.Now().Add(exportFlushTimeout))
+
+	// the headers are set only once the export has started, so that an export which fails
+	// before the first user is responded as an error, instead of an attachment
+	headersSet := false
+	setHeaders := func() {
+		if headersSet {
+			return
+		}
+		headersSet = true
+		w.Header().Set(webgo.HeaderContentType, format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="users.%s"`, format))
+	}
+
+	exported := 0
+	err = h.apis.ExportUsers(r.Context(), opts, func(user *users.User) error {
+		setHeaders()
+		err := writer.Write(user)
+		if err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushSize != 0 {
+			return nil
+		}
+
+		err = writer.Flush()
+		if err != nil {
+			return err
+		}
+		_ = rc.Flush()
+		_ = rc.SetWriteDeadline(time.Now().Add(exportFlushTimeout))
+
+		return nil
+	})
+	if err == nil {
+		setHeaders()
+		err = writer.Flush()
+	}
+
+	if err != nil && exported == 0 {
+		// nothing is written yet, so it can be responded with the error
+		return err
+	} else if err != nil {
+		// the error is not logged if the client has gone away
+		if r.Context().Err() == nil {
+			logger.Error(r.Context(), errors.Stacktrace(err))
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/api"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestExportUsers_Headers(t *testing.T) {
+	h := &Handlers{apis: api.New(users.NewService(users.NewMemoryStore(), nil), nil, nil)}
+
+	w := httptest.NewRecorder()
+	err := h.ExportUsers(w, httptest.NewRequest(http.MethodGet, "/users/export?format=csv&cursor=invalid", nil))
+	if errors.Type(err) != errors.TypeValidation {
+		t.Fatalf("expected an invalid cursor error, got %v", err)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "" {
+		t.Errorf("expected no Content-Disposition for a failed export, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	err = h.ExportUsers(w, httptest.NewRequest(http.MethodGet, "/users/export?format=csv", nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := w.Header().Get("Content-Type"); got != users.ExportCSV.ContentType() {
+		t.Errorf("expected Content-Type %q for an empty export, got %q", users.ExportCSV.ContentType(), got)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("expected the CSV header for an empty export")
+	}
+}
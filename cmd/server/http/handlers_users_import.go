@@ -0,0 +1,384 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/webgo/v7"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+const (
+	importBatchSize = 500
+	// importMaxLineSize is the maximum size of a single NDJSON line
+	importMaxLineSize = 1024 * 1024
+	// importBatchTimeout is the time allowed to read, save & respond with a single batch. The
+	// server's read/write timeouts apply to the whole request, which would limit the size of an import
+	importBatchTimeout = time.Minute
+	// importMaxErrors is the maximum number of failed lines in a buffered report, to keep the
+	// memory used by a single import bounded
+	importMaxErrors = 1000
+
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// ImportResult is the result of a single line of an import. The response of an import is
+// a stream of results, one JSON per line. If the import could not be completed, the last line
+// has only the Error, with Line as 0
+type ImportResult struct {
+	Line   int
+	ID     string          `json:",omitempty"`
+	Email  string          `json:",omitempty"`
+	Status users.RowStatus `json:",omitempty"`
+	Error  string          `json:",omitempty"`
+}
+
+// importRow is a single user read from the request body
+type importRow struct {
+	line int
+	user users.User
+	// err is set if the row could not be parsed
+	err error
+}
+
+// userDecoder reads users from the request body one row at a time, and returns io.EOF
+// once there are no more rows. Errors other than the ones of a row are returned as is
+type userDecoder interface {
+	next() (*importRow, error)
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (nd *ndjsonDecoder) next() (*importRow, error) {
+	for nd.scanner.Scan() {
+		nd.line++
+		raw := bytes.TrimSpace(nd.scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		row := &importRow{line: nd.line}
+		err := json.Unmarshal(raw, &row.user)
+		if err != nil {
+			row.err = errors.InputBodyErr(err, "invalid JSON provided")
+		}
+		return row, nil
+	}
+
+	err := nd.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return nil, errors.InputBodyErrf(err, "line %d is longer than %d bytes", nd.line+1, importMaxLineSize)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed reading request body")
+	}
+
+	return nil, io.EOF
+}
+
+func newNDJSONDecoder(body io.Reader) *ndjsonDecoder {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineSize)
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+type csvDecoder struct {
+	reader *csv.Reader
+	// columns has the index of every column in the header, and -1 for the ones not present
+	columns struct {
+		id, fullName, email, phone, contactAddress int
+	}
+}
+
+func (cd *csvDecoder) next() (*importRow, error) {
+	record, err := cd.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	perr := new(csv.ParseError)
+	if errors.As(err, &perr) {
+		// the reader can continue with the next record after a parse error
+		return &importRow{
+			line: perr.StartLine,
+			err:  errors.InputBodyErrf(err, "invalid CSV row, %s", perr.Err),
+		}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed reading request body")
+	}
+
+	line, _ := cd.reader.FieldPos(0)
+	field := func(idx int) string {
+		if idx < 0 {
+			return ""
+		}
+		return record[idx]
+	}
+
+	return &importRow{
+		line: line,
+		user: users.User{
+			ID:             field(cd.columns.id),
+			FullName:       field(cd.columns.fullName),
+			Email:          field(cd.columns.email),
+			Phone:          field(cd.columns.phone),
+			ContactAddress: field(cd.columns.contactAddress),
+		},
+	}, nil
+}
+
+// newCSVDecoder reads the header of the CSV. Column names are case insensitive, and
+// underscores & spaces are ignored. i.e. full_name, FullName & "Full Name" are all the same.
+// status, created_at & updated_at are ignored, so that a CSV export can be imported as is
+func newCSVDecoder(body io.Reader) (*csvDecoder, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.InputBody("empty CSV, a header is required")
+	} else if err != nil {
+		return nil, errors.InputBodyErr(err, "invalid CSV header")
+	}
+
+	cd := &csvDecoder{reader: reader}
+	cd.columns.id = -1
+	cd.columns.fullName = -1
+	cd.columns.email = -1
+	cd.columns.phone = -1
+	cd.columns.contactAddress = -1
+
+	for idx, name := range header {
+		if idx == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(name))
+
+		var column *int
+		switch name {
+		case "id":
+			column = &cd.columns.id
+		case "fullname":
+			column = &cd.columns.fullName
+		case "email":
+			column = &cd.columns.email
+		case "phone":
+			column = &cd.columns.phone
+		case "contactaddress":
+			column = &cd.columns.contactAddress
+		case "status", "createdat", "updatedat":
+			continue
+		default:
+			return nil, errors.InputBodyf("unknown CSV column %q", header[idx])
+		}
+
+		if *column >= 0 {
+			return nil, errors.InputBodyf("duplicate CSV column %q", header[idx])
+		}
+		*column = idx
+	}
+
+	if cd.columns.email < 0 {
+		return nil, errors.InputBody("CSV header must have the column email")
+	}
+
+	return cd, nil
+}
+
+func newUserDecoder(contentType string, body io.Reader) (userDecoder, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errors.InputBodyErr(err, "invalid Content-Type")
+	}
+
+	switch mediaType {
+	case contentTypeCSV:
+		return newCSVDecoder(body)
+	case contentTypeNDJSON, "application/ndjson":
+		return newNDJSONDecoder(body), nil
+	default:
+		return nil, errors.InputBodyf(
+			"unsupported Content-Type %q, expected %s or %s",
+			mediaType, contentTypeCSV, contentTypeNDJSON,
+		)
+	}
+}
+
+// importReport writes the result of every line as soon as it's available
+type importReport struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	encoder *json.Encoder
+	// buffered is set when the response can not be written while the request body is still
+	// being read. Then only the failed lines, up to importMaxErrors, are kept and written after
+	// the whole body is read. The counts are responded in the headers X-Import-Inserted &
+	// X-Import-Failed
+	buffered *bytes.Buffer
+	inserted int
+	failed   int
+}
+
+func (ir *importReport) write(results ...ImportResult) error {
+	for idx := range results {
+		if ir.buffered != nil && results[idx].Line > 0 {
+			if results[idx].Status == users.RowInserted {
+				ir.inserted++
+				continue
+			}
+			ir.failed++
+			if ir.failed > importMaxErrors {
+				continue
+			}
+		}
+
+		err := ir.encoder.Encode(results[idx])
+		if err != nil {
+			return errors.Wrap(err, "failed writing import result")
+		}
+	}
+
+	if ir.buffered == nil {
+		_ = ir.rc.Flush()
+	}
+
+	return nil
+}
+
+// extendDeadlines extends the read and write deadlines of the connection for another batch
+func (ir *importReport) extendDeadlines() {
+	deadline := time.Now().Add(importBatchTimeout)
+	_ = ir.rc.SetReadDeadline(deadline)
+	_ = ir.rc.SetWriteDeadline(deadline)
+}
+
+func (ir *importReport) fail(r *http.Request, err error) {
+	status, msg, _ := errors.HTTPStatusCodeMessage(err)
+	if status > 499 {
+		logger.Error(r.Context(), errors.Stacktrace(err))
+	}
+	_ = ir.write(ImportResult{Error: msg})
+}
+
+func (ir *importReport) close() {
+	if ir.buffered != nil {
+		ir.w.Header().Set("X-Import-Inserted", strconv.Itoa(ir.inserted))
+		ir.w.Header().Set("X-Import-Failed", strconv.Itoa(ir.failed))
+		ir.w.WriteHeader(http.StatusOK)
+		_, _ = ir.w.Write(ir.buffered.Bytes())
+	}
+}
+
+func newImportReport(w http.ResponseWriter, r *http.Request) *importReport {
+	ir := &importReport{
+		w:  w,
+		rc: http.NewResponseController(webgo.OriginalResponseWriter(w)),
+	}
+
+	// HTTP/2 is always full duplex, while with HTTP/1.x by default the request body
+	// can not be read anymore after the response is written
+	w.Header().Set(webgo.HeaderContentType, contentTypeNDJSON)
+	if r.ProtoMajor < 2 && ir.rc.EnableFullDuplex() != nil {
+		ir.buffered = bytes.NewBuffer(nil)
+		ir.encoder = json.NewEncoder(ir.buffered)
+	} else {
+		ir.encoder = json.NewEncoder(w)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return ir
+}
+
+// ImportUsers is the HTTP handler to create users in bulk from a CSV or NDJSON request body.
+// The body is read and saved in batches, and the result of every line is streamed back
+// as NDJSON. Invalid or duplicate users do not stop the import
+func (h *Handlers) ImportUsers(w http.ResponseWriter, r *http.Request) error {
+	decoder, err := newUserDecoder(r.Header.Get(webgo.HeaderContentType), r.Body)
+	if err != nil {
+		return err
+	}
+
+	report := newImportReport(w, r)
+	defer report.close()
+	report.extendDeadlines()
+
+	batch := make([]*importRow, 0, importBatchSize)
+	for {
+		row, err := decoder.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.fail(r, err)
+			return nil
+		}
+
+		batch = append(batch, row)
+		if len(batch) < importBatchSize {
+			continue
+		}
+
+		err = h.importBatch(r, report, batch)
+		if err != nil {
+			report.fail(r, err)
+			return nil
+		}
+		batch = batch[:0]
+		report.extendDeadlines()
+	}
+
+	err = h.importBatch(r, report, batch)
+	if err != nil {
+		report.fail(r, err)
+	}
+
+	return nil
+}
+
+func (h *Handlers) importBatch(r *http.Request, report *importReport, batch []*importRow) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	results := make([]ImportResult, len(batch))
+	valid := make([]users.User, 0, len(batch))
+	validRows := make([]int, 0, len(batch))
+	for idx, row := range batch {
+		results[idx].Line = row.line
+		if row.err != nil {
+			msg, _ := errors.Message(row.err)
+			results[idx].Status = users.RowInvalid
+			results[idx].Error = msg
+			continue
+		}
+		valid = append(valid, row.user)
+		validRows = append(validRows, idx)
+	}
+
+	rowResults, err := h.apis.BulkCreateUsers(r.Context(), valid)
+	if err != nil {
+		return err
+	}
+
+	for _, rres := range rowResults {
+		result := &results[validRows[rres.Row]]
+		result.ID = rres.ID
+		result.Email = rres.Email
+		result.Status = rres.Status
+		result.Error = rres.Error
+	}
+
+	return report.write(results...)
+}
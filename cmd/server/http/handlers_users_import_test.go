@@ -0,0 +1,63 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestImportReport_Buffered(t *testing.T) {
+	w := httptest.NewRecorder()
+	buffered := bytes.NewBuffer(nil)
+	report := &importReport{
+		w:        w,
+		encoder:  json.NewEncoder(buffered),
+		buffered: buffered,
+	}
+
+	results := make([]ImportResult, 0, importMaxErrors*2)
+	for line := 1; line <= importMaxErrors*2; line++ {
+		status := users.RowInserted
+		if line%2 == 0 {
+			status = users.RowInvalid
+		}
+		results = append(results, ImportResult{Line: line, Status: status})
+	}
+	// one more failure than can be kept, along with as many inserted
+	results = append(results, ImportResult{Line: len(results) + 1, Status: users.RowDuplicate})
+
+	err := report.write(results...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.close()
+
+	if got := w.Header().Get("X-Import-Inserted"); got != "1000" {
+		t.Errorf("expected 1000 inserted, got %q", got)
+	}
+	if got := w.Header().Get("X-Import-Failed"); got != "1001" {
+		t.Errorf("expected 1001 failed, got %q", got)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		result := ImportResult{}
+		err = json.Unmarshal(scanner.Bytes(), &result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Status == users.RowInserted {
+			t.Errorf("expected only the failed lines, got line %d inserted", result.Line)
+		}
+		lines++
+	}
+
+	if lines != importMaxErrors {
+		t.Errorf("expected %d failed lines, got %d", importMaxErrors, lines)
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/webgo/v7"
+)
+
+// SendUserVerification is the HTTP handler to email a verification link to the user in the URI
+func (h *Handlers) SendUserVerification(w http.ResponseWriter, r *http.Request) error {
+	wctx := webgo.Context(r)
+	userID := wctx.Params()["id"]
+
+	expiresAt, err := h.apis.SendUserVerification(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	webgo.SendResponse(w, map[string]time.Time{"ExpiresAt": expiresAt}, http.StatusAccepted)
+
+	return nil
+}
+
+// VerifyUser is the HTTP handler for the verification link, the token is in the query param 'token'
+func (h *Handlers) VerifyUser(w http.ResponseWriter, r *http.Request) error {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return errors.InputBody("token is required")
+	}
+
+	u, err := h.apis.VerifyUser(r.Context(), token)
+	if err != nil {
+		return err
+	}
+
+	webgo.R200(w, u)
+
+	return nil
+}
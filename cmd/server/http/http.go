@@ -11,6 +11,7 @@ import (
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/goapp/internal/api"
 	"github.com/naughtygopher/goapp/internal/pkg/apm"
+	"github.com/naughtygopher/goapp/internal/pkg/idempotency"
 	"github.com/naughtygopher/webgo/v7"
 	"github.com/naughtygopher/webgo/v7/middleware/accesslog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -49,16 +50,24 @@ func (h *HTTP) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// NewService returns an instance of HTTP with all its dependencies set
-func NewService(cfg *Config, apis api.Server) (*HTTP, error) {
+// NewService returns an instance of HTTP with all its dependencies set. idem is used
+// to make the create APIs idempotent, and gateway is served under /api/v2
+func NewService(
+	cfg *Config,
+	apis api.Server,
+	idem *idempotency.Idempotency,
+	gateway http.Handler,
+) (*HTTP, error) {
 	home, err := loadHomeTemplate(cfg.TemplatesBasePath)
 	if err != nil {
 		return nil, err
 	}
 
 	handlers := &Handlers{
-		apis: apis,
-		home: home,
+		apis:        apis,
+		home:        home,
+		idempotency: idem,
+		gateway:     gateway,
 	}
 
 	router := webgo.NewRouter(
@@ -69,7 +78,7 @@ func NewService(cfg *Config, apis api.Server) (*HTTP, error) {
 			WriteTimeout:    cfg.WriteTimeout,
 			ShutdownTimeout: cfg.WriteTimeout * 2,
 		},
-		handlers.routes()...,
+		append(handlers.routes(), handlers.gatewayRoutes()...)...,
 	)
 
 	if cfg.EnableAccessLog {
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/configs"
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/pkg/postgres"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+const exportUsage = "usage: export [-format ndjson|csv] [-out path] [-sort order] [-email-domain domain] [-name-prefix prefix] [-created-after RFC3339] [-created-before RFC3339]"
+
+func exportOptions(args []string) (users.ExportFormat, string, *users.ListOptions, error) {
+	var (
+		fset          = flag.NewFlagSet("export", flag.ContinueOnError)
+		format        = fset.String("format", string(users.ExportNDJSON), "ndjson or csv")
+		out           = fset.String("out", "", "path of the file to export to, default users.<format>")
+		sort          = fset.String("sort", string(users.SortCreatedAtAsc), "created_at, -created_at, email or -email")
+		emailDomain   = fset.String("email-domain", "", "export only the users with the email domain")
+		namePrefix    = fset.String("name-prefix", "", "export only the users whose name starts with the prefix")
+		createdAfter  = fset.String("created-after", "", "export only the users created at or after, RFC3339")
+		createdBefore = fset.String("created-before", "", "export only the users created before, RFC3339")
+	)
+	fset.SetOutput(io.Discard)
+
+	err := fset.Parse(args)
+	if err != nil {
+		return "", "", nil, errors.ValidationErr(err, exportUsage)
+	}
+
+	opts := &users.ListOptions{
+		Sort: users.SortOrder(*sort),
+		Filter: users.ListFilter{
+			EmailDomain: *emailDomain,
+			NamePrefix:  *namePrefix,
+		},
+	}
+
+	if *createdAfter != "" {
+		opts.Filter.CreatedAfter, err = time.Parse(time.RFC3339, *createdAfter)
+		if err != nil {
+			return "", "", nil, errors.ValidationErr(err, "invalid created-after provided, expected RFC3339")
+		}
+	}
+
+	if *createdBefore != "" {
+		opts.Filter.CreatedBefore, err = time.Parse(time.RFC3339, *createdBefore)
+		if err != nil {
+			return "", "", nil, errors.ValidationErr(err, "invalid created-before provided, expected RFC3339")
+		}
+	}
+
+	err = users.ExportFormat(*format).Validate()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if *out == "" {
+		*out = "users." + *format
+	}
+
+	return users.ExportFormat(*format), *out, opts, nil
+}
+
+// runExportCommand is the 'export' subcommand of the app, args are the arguments after 'export'.
+// Users are exported to a temporary file, which is renamed to the output path only once the
+// export is complete. So a failed export never leaves behind a partial file
+func runExportCommand(ctx context.Context, cfgs *configs.Configs, args []string) error {
+	format, out, opts, err := exportOptions(args)
+	if err != nil {
+		return err
+	}
+
+	if cfgs.Store() != configs.StorePostgres {
+		return errors.Validationf("export requires the %s store", configs.StorePostgres)
+	}
+
+	pqdriver, err := postgres.NewPool(cfgs.Postgres())
+	if err != nil {
+		return err
+	}
+	defer pqdriver.Close()
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*.partial")
+	if err != nil {
+		return errors.Wrap(err, "failed creating export file")
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	buff := bufio.NewWriter(tmpFile)
+	writer, err := users.NewExportWriter(buff, format)
+	if err != nil {
+		return err
+	}
+
+	svc := users.NewService(users.NewPostgresStore(pqdriver, cfgs.UserPostgresTable()), nil)
+	exported := 0
+	err = svc.ExportUsers(ctx, opts, func(user *users.User) error {
+		exported++
+		return writer.Write(user)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = buff.Flush()
+	if err != nil {
+		return errors.Wrap(err, "failed writing export file")
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed writing export file")
+	}
+
+	err = os.Rename(tmpFile.Name(), out)
+	if err != nil {
+		return errors.Wrap(err, "failed renaming export file")
+	}
+
+	logger.Info(ctx, fmt.Sprintf("[export] exported %d users to %s", exported, out))
+
+	return nil
+}
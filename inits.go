@@ -5,46 +5,70 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/proberesponder"
 	"github.com/naughtygopher/proberesponder/extensions/depprober"
 	proberespHTTP "github.com/naughtygopher/proberesponder/extensions/http"
 	"github.com/naughtygopher/webgo/v7"
 
-	"github.com/naughtygopher/goapp/cmd/server/grpc"
+	xgrpc "github.com/naughtygopher/goapp/cmd/server/grpc"
 	xhttp "github.com/naughtygopher/goapp/cmd/server/http"
 	"github.com/naughtygopher/goapp/internal/api"
 	"github.com/naughtygopher/goapp/internal/configs"
 	"github.com/naughtygopher/goapp/internal/pkg/apm"
+	"github.com/naughtygopher/goapp/internal/pkg/idempotency"
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
+	"github.com/naughtygopher/goapp/internal/pkg/lifecycle"
 	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/pkg/mailer"
 	"github.com/naughtygopher/goapp/internal/pkg/postgres"
+	"github.com/naughtygopher/goapp/internal/usernotes"
 	"github.com/naughtygopher/goapp/internal/users"
+	"github.com/naughtygopher/goapp/internal/verification"
 )
 
 var now = time.Now()
 
-func startAPM(ctx context.Context, cfg *configs.Configs) *apm.APM {
-	ap, err := apm.New(ctx, &apm.Options{
-		Debug:            cfg.Environment == configs.EnvLocal,
-		Environment:      cfg.Environment.String(),
-		ServiceName:      cfg.AppName,
-		ServiceVersion:   cfg.AppVersion,
-		TracesSampleRate: 50.00,
-		UseStdOut:        cfg.Environment == configs.EnvLocal,
-	})
-	if err != nil {
-		panic(errors.Wrap(err, "failed to start APM"))
+func newLogger(cfgs *configs.Configs) *logger.LogHandler {
+	return logger.New(
+		cfgs.AppName, cfgs.AppVersion, 0,
+		map[string]string{
+			"env": cfgs.Environment.String(),
+		},
+	)
+}
+
+// newMailer returns the mailer of the configured type
+func newMailer(cfgs *configs.Configs) mailer.Mailer {
+	switch cfgs.MailerType() {
+	case configs.MailerSMTP:
+		return mailer.NewSMTP(cfgs.SMTP())
+	case configs.MailerFile:
+		return mailer.NewFile(cfgs.MailerFilePath(), cfgs.MailerFrom())
+	default:
+		return mailer.NewLog(cfgs.MailerFrom())
 	}
-	return ap
 }
 
-func startServers(svr api.Server, cfgs *configs.Configs, fatalErr chan<- error) (*xhttp.HTTP, *grpc.GRPC) {
+func startHTTPServer(
+	svr api.Server,
+	idem *idempotency.Idempotency,
+	cfgs *configs.Configs,
+	fatalErr chan<- error,
+) (*xhttp.HTTP, error) {
+	gateway, err := xgrpc.NewGateway(svr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize gRPC gateway")
+	}
+
 	hcfg, _ := cfgs.HTTP()
-	hserver, err := xhttp.NewService(hcfg, svr)
+	hserver, err := xhttp.NewService(hcfg, svr, idem, gateway)
 	if err != nil {
-		fatalErr <- errors.Wrap(err, "failed to initialize HTTP server")
+		return nil, errors.Wrap(err, "failed to initialize HTTP server")
 	}
 
 	go func() {
@@ -54,7 +78,7 @@ func startServers(svr api.Server, cfgs *configs.Configs, fatalErr chan<- error)
 				fatalErr <- errors.New(fmt.Sprintf("%+v", rec))
 			}
 		}()
-		err = hserver.Start()
+		err := hserver.Start()
 		if err != nil {
 			fatalErr <- errors.Wrap(err, "failed to start HTTP server")
 		}
@@ -63,6 +87,30 @@ func startServers(svr api.Server, cfgs *configs.Configs, fatalErr chan<- error)
 	return hserver, nil
 }
 
+func startGRPCServer(
+	svr api.Server,
+	probestatus *proberesponder.ProbeResponder,
+	cfgs *configs.Configs,
+	fatalErr chan<- error,
+) (*xgrpc.GRPC, error) {
+	gserver := xgrpc.New(cfgs.GRPC(), svr, probestatus)
+
+	go func() {
+		defer func() {
+			rec := recover()
+			if rec != nil {
+				fatalErr <- errors.New(fmt.Sprintf("%+v", rec))
+			}
+		}()
+		err := gserver.Start()
+		if err != nil {
+			fatalErr <- errors.Wrap(err, "failed to start gRPC server")
+		}
+	}()
+
+	return gserver, nil
+}
+
 func healthResponseHandler(ps *proberesponder.ProbeResponder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		payload := map[string]any{
@@ -83,15 +131,34 @@ func healthResponseHandler(ps *proberesponder.ProbeResponder) http.HandlerFunc {
 	}
 }
 
-func startHealthResponder(ctx context.Context, ps *proberesponder.ProbeResponder, fatalErr chan<- error) (*http.Server, error) {
-	port := uint32(2000)
+// configResponseHandler responds with the active configuration, with all the secrets redacted
+func configResponseHandler(cfgs *atomic.Pointer[configs.Configs]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := json.Marshal(cfgs.Load().Redacted())
+		w.Header().Add(webgo.HeaderContentType, webgo.JSONContentType)
+		_, _ = w.Write(b)
+	}
+}
+
+func startHealthResponder(
+	ctx context.Context,
+	ps *proberesponder.ProbeResponder,
+	cfgs *atomic.Pointer[configs.Configs],
+	fatalErr chan<- error,
+) (*http.Server, error) {
+	port := cfgs.Load().HealthPort()
 	srv := proberespHTTP.Server(
-		ps, "", uint16(port),
+		ps, "", port,
 		proberespHTTP.Handler{
 			Method:  http.MethodGet,
 			Path:    "/-/health",
 			Handler: healthResponseHandler(ps),
 		},
+		proberespHTTP.Handler{
+			Method:  http.MethodGet,
+			Path:    "/-/config",
+			Handler: configResponseHandler(cfgs),
+		},
 	)
 
 	go func() {
@@ -103,16 +170,22 @@ func startHealthResponder(ctx context.Context, ps *proberesponder.ProbeResponder
 	return srv, nil
 }
 
-func start(
+func startPostgres(
 	ctx context.Context,
 	probestatus *proberesponder.ProbeResponder,
 	cfgs *configs.Configs,
-	fatalErr chan<- error,
-) (hserver *xhttp.HTTP, gserver *grpc.GRPC) {
-	_ = ctx
+) (*pgxpool.Pool, error) {
 	pqdriver, err := postgres.NewPool(cfgs.Postgres())
 	if err != nil {
-		panic(errors.Wrap(err))
+		return nil, errors.Wrap(err)
+	}
+
+	if cfgs.MigrateOnStart() {
+		err = migrateUp(ctx, pqdriver)
+		if err != nil {
+			pqdriver.Close()
+			return nil, errors.Wrap(err, "failed applying migrations")
+		}
 	}
 
 	depprober.Start(time.Minute, probestatus, &depprober.Probe{
@@ -127,9 +200,180 @@ func start(
 		}),
 	})
 
-	userPGstore := users.NewPostgresStore(pqdriver, cfgs.UserPostgresTable())
-	userSvc := users.NewService(userPGstore)
-	svrAPIs := api.NewServer(userSvc, nil)
-	hserver, gserver = startServers(svrAPIs, cfgs, fatalErr)
-	return
+	return pqdriver, nil
+}
+
+// stopTimeout returns the share (in percent) of the time available for stopping the components,
+// i.e. the shutdown grace period remaining after waiting for the probes. The shares of all the
+// components add up to 100, so that a slow component can not use up the time of the ones after it
+func stopTimeout(cfgs *configs.Configs, percent int) time.Duration {
+	available := cfgs.ShutdownGracePeriod() - cfgs.ProbeInterval()
+	return available * time.Duration(percent) / 100
+}
+
+// start registers all the components of the app with the lifecycle manager, and starts them.
+// Even if start fails, the returned manager should be used to stop the components started until then
+func start(
+	ctx context.Context,
+	probestatus *proberesponder.ProbeResponder,
+	cfgs *configs.Configs,
+	fatalErr chan<- error,
+) (*lifecycle.Manager, error) {
+	var (
+		lc           = lifecycle.New()
+		apmIns       *apm.APM
+		pqdriver     *pgxpool.Pool
+		svrAPIs      api.Server
+		jobs         *jobqueue.Queue
+		idem         *idempotency.Idempotency
+		hserver      *xhttp.HTTP
+		gserver      *xgrpc.GRPC
+		apiDependsOn = []string{"apm"}
+	)
+
+	components := []*lifecycle.Component{
+		{
+			Name: "apm",
+			Start: func(ctx context.Context) (err error) {
+				apmIns, err = apm.New(ctx, cfgs.APM())
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return apmIns.Shutdown(ctx)
+			},
+			// flushes the pending traces & metrics
+			StopTimeout: stopTimeout(cfgs, 10),
+		},
+	}
+
+	if cfgs.Store() == configs.StorePostgres {
+		apiDependsOn = append(apiDependsOn, "postgres")
+		components = append(components, &lifecycle.Component{
+			Name: "postgres",
+			Start: func(ctx context.Context) (err error) {
+				pqdriver, err = startPostgres(ctx, probestatus, cfgs)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				// Close waits for all the acquired connections to be released
+				pqdriver.Close()
+				return nil
+			},
+			StopTimeout: stopTimeout(cfgs, 10),
+		})
+	}
+
+	components = append(
+		components,
+		&lifecycle.Component{
+			Name:      "api",
+			DependsOn: apiDependsOn,
+			Start: func(ctx context.Context) error {
+				var (
+					userSvc         *users.Users
+					userNotesSvc    *usernotes.UserNotes
+					verificationSvc *verification.Verification
+				)
+
+				if pqdriver == nil {
+					logger.Warn(ctx, "using in-memory stores, all data will be lost on exit")
+					jobs = jobqueue.New(jobqueue.NewMemoryStore(), cfgs.Jobs())
+					idem = idempotency.New(idempotency.NewMemoryStore(), cfgs.Idempotency())
+					userSvc = users.NewService(users.NewMemoryStore(), jobs)
+					userNotesSvc = usernotes.NewService(usernotes.NewMemoryStore())
+					verificationSvc = verification.NewService(
+						verification.NewMemoryStore(),
+						userSvc,
+						newMailer(cfgs),
+						cfgs.Verification(),
+					)
+				} else {
+					jobs = jobqueue.New(
+						jobqueue.NewPostgresStore(pqdriver, cfgs.JobsPostgresTable()),
+						cfgs.Jobs(),
+					)
+					idem = idempotency.New(
+						idempotency.NewPostgresStore(pqdriver, cfgs.IdempotencyPostgresTable()),
+						cfgs.Idempotency(),
+					)
+
+					userPGstore := users.NewPostgresStore(pqdriver, cfgs.UserPostgresTable())
+					userSvc = users.NewService(userPGstore, jobs)
+
+					userNotesPGstore := usernotes.NewPostgresStore(pqdriver, cfgs.UserNotesPostgresTable())
+					userNotesSvc = usernotes.NewService(userNotesPGstore)
+
+					verificationSvc = verification.NewService(
+						verification.NewPostgresStore(pqdriver, cfgs.VerificationPostgresTable()),
+						userSvc,
+						newMailer(cfgs),
+						cfgs.Verification(),
+					)
+				}
+
+				svrAPIs = api.NewServer(userSvc, userNotesSvc, verificationSvc)
+				return nil
+			},
+		},
+		&lifecycle.Component{
+			// job handlers are registered by the services, so the workers are started after "api".
+			// And when stopping, the jobs in progress are drained before the dependencies are closed
+			Name:      "jobs",
+			DependsOn: []string{"api"},
+			Start: func(ctx context.Context) error {
+				return jobs.Start(ctx)
+			},
+			Stop: func(ctx context.Context) error {
+				return jobs.Shutdown(ctx)
+			},
+			// the jobs in progress are allowed to complete
+			StopTimeout: stopTimeout(cfgs, 30),
+		},
+		&lifecycle.Component{
+			// purges the expired idempotency keys
+			Name:      "idempotency",
+			DependsOn: []string{"api"},
+			Start: func(ctx context.Context) error {
+				return idem.Start(ctx)
+			},
+			Stop: func(ctx context.Context) error {
+				return idem.Shutdown(ctx)
+			},
+			StopTimeout: stopTimeout(cfgs, 5),
+		},
+		&lifecycle.Component{
+			Name:      "http",
+			DependsOn: []string{"api"},
+			Start: func(ctx context.Context) (err error) {
+				hserver, err = startHTTPServer(svrAPIs, idem, cfgs, fatalErr)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return hserver.Shutdown(ctx)
+			},
+			// the requests in progress, e.g. imports & exports, are allowed to complete
+			StopTimeout: stopTimeout(cfgs, 30),
+		},
+		&lifecycle.Component{
+			Name:      "grpc",
+			DependsOn: []string{"api"},
+			Start: func(ctx context.Context) (err error) {
+				gserver, err = startGRPCServer(svrAPIs, probestatus, cfgs, fatalErr)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return gserver.Shutdown(ctx)
+			},
+			StopTimeout: stopTimeout(cfgs, 15),
+		},
+	)
+
+	for _, comp := range components {
+		err := lc.Register(comp)
+		if err != nil {
+			return lc, err
+		}
+	}
+
+	return lc, lc.Start(ctx)
 }
@@ -2,40 +2,57 @@ package api
 
 import (
 	"context"
+	"time"
 
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
 	"github.com/naughtygopher/goapp/internal/usernotes"
 	"github.com/naughtygopher/goapp/internal/users"
+	"github.com/naughtygopher/goapp/internal/verification"
 )
 
 // Server has all the methods required to run the server
 type Server interface {
 	CreateUser(ctx context.Context, user *users.User) (*users.User, error)
 	ReadUserByEmail(ctx context.Context, email string) (*users.User, error)
+	ReadUserByID(ctx context.Context, userID string) (*users.User, error)
+	UpdateUser(ctx context.Context, userID string, uu *users.UserUpdate) (*users.User, error)
+	DeleteUser(ctx context.Context, userID string) error
+	ListUsers(ctx context.Context, opts *users.ListOptions) (*users.List, error)
+	BulkCreateUsers(ctx context.Context, users []users.User) ([]users.RowResult, error)
+	ExportUsers(ctx context.Context, opts *users.ListOptions, each func(*users.User) error) error
 	CreateUserNote(ctx context.Context, un *usernotes.Note) (*usernotes.Note, error)
 	ReadUserNote(ctx context.Context, userID string, noteID string) (*usernotes.Note, error)
+	UpdateUserNote(ctx context.Context, userID string, noteID string, nu *usernotes.NoteUpdate, version time.Time) (*usernotes.Note, error)
+	DeleteUserNote(ctx context.Context, userID string, noteID string, version time.Time) error
+	ListUserNotes(ctx context.Context, userID string, cursor string, limit int, sort usernotes.SortOrder) (*usernotes.NoteList, error)
+	ReadJob(ctx context.Context, jobID string) (*jobqueue.Job, error)
+	SendUserVerification(ctx context.Context, userID string) (time.Time, error)
+	VerifyUser(ctx context.Context, token string) (*users.User, error)
 }
 
 // Subscriber has all the methods required to run the subscriber
 type Subscriber interface {
-	AsyncCreateUsers(ctcx context.Context, users []users.User) error
+	AsyncCreateUsers(ctx context.Context, users []users.User) (string, error)
 }
 
 type API struct {
-	users  *users.Users
-	unotes *usernotes.UserNotes
+	users        *users.Users
+	unotes       *usernotes.UserNotes
+	verification *verification.Verification
 }
 
-func New(us *users.Users, un *usernotes.UserNotes) *API {
+func New(us *users.Users, un *usernotes.UserNotes, vr *verification.Verification) *API {
 	return &API{
-		users:  us,
-		unotes: un,
+		users:        us,
+		unotes:       un,
+		verification: vr,
 	}
 }
 
-func NewServer(us *users.Users, un *usernotes.UserNotes) Server {
-	return New(us, un)
+func NewServer(us *users.Users, un *usernotes.UserNotes, vr *verification.Verification) Server {
+	return New(us, un, vr)
 }
 
 func NewSubscriber(us *users.Users) Subscriber {
-	return New(us, nil)
+	return New(us, nil, nil)
 }
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"context"
+
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
+)
+
+// ReadJob returns a background job created by any of the async APIs, e.g. AsyncCreateUsers
+func (a *API) ReadJob(ctx context.Context, jobID string) (*jobqueue.Job, error) {
+	return a.users.ReadBulkCreateJob(ctx, jobID)
+}
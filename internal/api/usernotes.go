@@ -2,16 +2,81 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/naughtygopher/errors"
 
 	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
 )
 
+// CreateUserNote is the API to create a new note for a user. Only verified users can create notes
 func (a *API) CreateUserNote(ctx context.Context, un *usernotes.Note) (*usernotes.Note, error) {
-	return nil, errors.New("create user not is not implemented")
+	if un != nil && un.Creator != nil && un.Creator.ID != "" {
+		creator, err := a.users.ReadByID(ctx, un.Creator.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// the user is known but not permitted, i.e. 403 (not 401) and PermissionDenied for gRPC
+		if !creator.IsVerified() {
+			return nil, errors.UnauthorizedErr(users.ErrUserNotVerified, "verify the email to create notes")
+		}
+	}
+
+	note, err := a.unotes.SaveNote(ctx, un)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
 }
 
+// ReadUserNote is the API to read an existing note of a user
 func (a *API) ReadUserNote(ctx context.Context, userID string, noteID string) (*usernotes.Note, error) {
-	return nil, errors.New("read user not is not implemented")
+	note, err := a.unotes.GetNoteByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// ListUserNotes is the API to list notes of a user, one page at a time
+func (a *API) ListUserNotes(
+	ctx context.Context,
+	userID string,
+	cursor string,
+	limit int,
+	sort usernotes.SortOrder,
+) (*usernotes.NoteList, error) {
+	list, err := a.unotes.ListNotes(ctx, userID, cursor, limit, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateUserNote is the API to partially update a note of a user. A non-zero version makes the
+// update conditional on the note not having been modified since
+func (a *API) UpdateUserNote(
+	ctx context.Context,
+	userID string,
+	noteID string,
+	nu *usernotes.NoteUpdate,
+	version time.Time,
+) (*usernotes.Note, error) {
+	note, err := a.unotes.UpdateNote(ctx, userID, noteID, nu, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// DeleteUserNote is the API to delete a note of a user. A non-zero version makes the
+// deletion conditional on the note not having been modified since
+func (a *API) DeleteUserNote(ctx context.Context, userID string, noteID string, version time.Time) error {
+	return a.unotes.DeleteNote(ctx, userID, noteID, version)
 }
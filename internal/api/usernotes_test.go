@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// newTestAPI returns the API backed by in-memory stores, along with a verified user
+func newTestAPI(t *testing.T) (*API, *users.User) {
+	t.Helper()
+
+	ctx := context.Background()
+	us := users.NewService(users.NewMemoryStore(), nil)
+	a := New(us, usernotes.NewService(usernotes.NewMemoryStore()), nil)
+
+	user, err := a.CreateUser(ctx, &users.User{FullName: "Jane Doe", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+
+	err = us.MarkVerified(ctx, user.ID, user.Email)
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+
+	return a, user
+}
+
+func TestUserNotes_CreateAndRead(t *testing.T) {
+	ctx := context.Background()
+	a, user := newTestAPI(t)
+
+	created, err := a.CreateUserNote(ctx, &usernotes.Note{
+		Title:   "title",
+		Content: "content",
+		Creator: &users.User{ID: user.ID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected the ID of the note to be set")
+	}
+
+	read, err := a.ReadUserNote(ctx, user.ID, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+	if read.ID != created.ID || read.Title != "title" || read.Content != "content" {
+		t.Fatalf("expected %+v, got %+v", created, read)
+	}
+	if read.Creator == nil || read.Creator.ID != user.ID {
+		t.Fatalf("expected creator %s, got %+v", user.ID, read.Creator)
+	}
+	if !read.UpdatedAt.Equal(created.UpdatedAt) {
+		t.Fatalf("expected updated at %s, got %s", created.UpdatedAt, read.UpdatedAt)
+	}
+}
+
+func TestUserNotes_ReadNotFound(t *testing.T) {
+	ctx := context.Background()
+	a, user := newTestAPI(t)
+
+	_, err := a.ReadUserNote(ctx, user.ID, "00000000-0000-0000-0000-000000000000")
+	if !errors.Is(err, usernotes.ErrNoteNotFound) {
+		t.Fatalf("expected ErrNoteNotFound, got %+v", err)
+	}
+	if errors.Type(err) != errors.TypeNotFound {
+		t.Fatalf("expected a not found error, got %+v", err)
+	}
+
+	// a note of a different user is not found either
+	created, err := a.CreateUserNote(ctx, &usernotes.Note{
+		Title:   "title",
+		Content: "content",
+		Creator: &users.User{ID: user.ID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+
+	_, err = a.ReadUserNote(ctx, "00000000-0000-0000-0000-000000000000", created.ID)
+	if errors.Type(err) != errors.TypeNotFound {
+		t.Fatalf("expected a not found error, got %+v", err)
+	}
+}
+
+func TestUserNotes_CreateUnverified(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestAPI(t)
+
+	user, err := a.CreateUser(ctx, &users.User{FullName: "John Doe", Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+
+	_, err = a.CreateUserNote(ctx, &usernotes.Note{
+		Title:   "title",
+		Content: "content",
+		Creator: &users.User{ID: user.ID},
+	})
+	if !errors.Is(err, users.ErrUserNotVerified) || errors.Type(err) != errors.TypeUnauthorized {
+		t.Fatalf("expected an unauthorized ErrUserNotVerified, got %+v", err)
+	}
+}
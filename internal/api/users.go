@@ -26,6 +26,52 @@ func (a *API) ReadUserByEmail(ctx context.Context, email string) (*users.User, e
 	return u, nil
 }
 
-func (a *API) AsyncCreateUsers(ctx context.Context, users []users.User) error {
+// ReadUserByID is the API to read an existing user by their ID
+func (a *API) ReadUserByID(ctx context.Context, userID string) (*users.User, error) {
+	u, err := a.users.ReadByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UpdateUser is the API to partially update an existing user
+func (a *API) UpdateUser(ctx context.Context, userID string, uu *users.UserUpdate) (*users.User, error) {
+	u, err := a.users.UpdateUser(ctx, userID, uu)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// DeleteUser is the API to delete an existing user
+func (a *API) DeleteUser(ctx context.Context, userID string) error {
+	return a.users.DeleteUser(ctx, userID)
+}
+
+// ListUsers is the API to list users, one page at a time
+func (a *API) ListUsers(ctx context.Context, opts *users.ListOptions) (*users.List, error) {
+	list, err := a.users.ListUsers(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// BulkCreateUsers is the API to create users in bulk, invalid or duplicate users do not
+// prevent the rest from being created
+func (a *API) BulkCreateUsers(ctx context.Context, us []users.User) ([]users.RowResult, error) {
+	return a.users.BulkCreateUsers(ctx, us)
+}
+
+// ExportUsers is the API to read all the users matching the filters, one user at a time
+func (a *API) ExportUsers(ctx context.Context, opts *users.ListOptions, each func(*users.User) error) error {
+	return a.users.ExportUsers(ctx, opts, each)
+}
+
+func (a *API) AsyncCreateUsers(ctx context.Context, users []users.User) (string, error) {
 	return a.users.AsyncCreateUsers(ctx, users)
 }
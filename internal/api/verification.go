@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// SendUserVerification is the API to email a verification link to the user, it returns the
+// time at which the link expires
+func (a *API) SendUserVerification(ctx context.Context, userID string) (time.Time, error) {
+	return a.verification.Send(ctx, userID)
+}
+
+// VerifyUser is the API to verify the email of a user, using the token from the verification link
+func (a *API) VerifyUser(ctx context.Context, token string) (*users.User, error) {
+	userID, err := a.verification.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := a.users.ReadByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
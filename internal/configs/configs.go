@@ -1,12 +1,21 @@
 package configs
 
 import (
-	"os"
+	"net/mail"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/cmd/server/grpc"
 	"github.com/naughtygopher/goapp/cmd/server/http"
+	"github.com/naughtygopher/goapp/internal/pkg/apm"
+	"github.com/naughtygopher/goapp/internal/pkg/idempotency"
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
+	"github.com/naughtygopher/goapp/internal/pkg/mailer"
 	"github.com/naughtygopher/goapp/internal/pkg/postgres"
+	"github.com/naughtygopher/goapp/internal/verification"
 )
 
 type env string
@@ -15,6 +24,19 @@ func (e env) String() string {
 	return string(e)
 }
 
+// Validate returns an error if the environment is not one of the known environments
+func (e env) Validate() error {
+	switch e {
+	case EnvLocal, EnvTest, EnvStaging, EnvProduction:
+		return nil
+	default:
+		return errors.Validationf(
+			"environment should be one of '%s', '%s', '%s', '%s', got '%s'",
+			EnvLocal, EnvTest, EnvStaging, EnvProduction, e,
+		)
+	}
+}
+
 const (
 	EnvLocal      env = "local"
 	EnvTest       env = "test"
@@ -22,71 +44,550 @@ const (
 	EnvProduction env = "production"
 )
 
+const (
+	StorePostgres = "postgres"
+	StoreMemory   = "memory"
+)
+
+const (
+	MailerSMTP = "smtp"
+	// MailerFile appends all the emails to a file, meant for local development
+	MailerFile = "file"
+	// MailerLog logs all the emails, meant for local development & tests
+	MailerLog = "log"
+)
+
+// Duration is time.Duration which can be unmarshaled from strings like "5s", "1m30s" etc.
+// in all the supported config file formats
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Duration returns the value as time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+type HTTPConfig struct {
+	Host              string   `json:"host" yaml:"host" toml:"host"`
+	Port              uint16   `json:"port" yaml:"port" toml:"port"`
+	ReadTimeout       Duration `json:"readTimeout" yaml:"readTimeout" toml:"readTimeout"`
+	WriteTimeout      Duration `json:"writeTimeout" yaml:"writeTimeout" toml:"writeTimeout"`
+	DialTimeout       Duration `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
+	TemplatesBasePath string   `json:"templatesBasePath" yaml:"templatesBasePath" toml:"templatesBasePath"`
+	AccessLog         bool     `json:"accessLog" yaml:"accessLog" toml:"accessLog"`
+}
+
+type GRPCConfig struct {
+	Host string `json:"host" yaml:"host" toml:"host"`
+	Port uint16 `json:"port" yaml:"port" toml:"port"`
+}
+
+type PostgresConfig struct {
+	Host           string   `json:"host" yaml:"host" toml:"host"`
+	Port           string   `json:"port" yaml:"port" toml:"port"`
+	StoreName      string   `json:"storeName" yaml:"storeName" toml:"storeName"`
+	Username       string   `json:"username" yaml:"username" toml:"username"`
+	Password       string   `json:"password" yaml:"password" toml:"password"`
+	SSLMode        string   `json:"sslMode" yaml:"sslMode" toml:"sslMode"`
+	ConnPoolSize   uint     `json:"connPoolSize" yaml:"connPoolSize" toml:"connPoolSize"`
+	ReadTimeout    Duration `json:"readTimeout" yaml:"readTimeout" toml:"readTimeout"`
+	WriteTimeout   Duration `json:"writeTimeout" yaml:"writeTimeout" toml:"writeTimeout"`
+	IdleTimeout    Duration `json:"idleTimeout" yaml:"idleTimeout" toml:"idleTimeout"`
+	DialTimeout    Duration `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
+	UsersTable     string   `json:"usersTable" yaml:"usersTable" toml:"usersTable"`
+	UserNotesTable string   `json:"userNotesTable" yaml:"userNotesTable" toml:"userNotesTable"`
+}
+
+type APMConfig struct {
+	CollectorURL         string  `json:"collectorURL" yaml:"collectorURL" toml:"collectorURL"`
+	TracesSampleRate     float64 `json:"tracesSampleRate" yaml:"tracesSampleRate" toml:"tracesSampleRate"`
+	PrometheusScrapePort uint16  `json:"prometheusScrapePort" yaml:"prometheusScrapePort" toml:"prometheusScrapePort"`
+	// UseStdOut if true, exports the traces and metrics to stdout instead of the collector
+	UseStdOut bool `json:"useStdOut" yaml:"useStdOut" toml:"useStdOut"`
+	Debug     bool `json:"debug" yaml:"debug" toml:"debug"`
+}
+
+// JobsConfig has the configurations of the background job queue
+type JobsConfig struct {
+	Workers      int      `json:"workers" yaml:"workers" toml:"workers"`
+	PollInterval Duration `json:"pollInterval" yaml:"pollInterval" toml:"pollInterval"`
+	MaxAttempts  int      `json:"maxAttempts" yaml:"maxAttempts" toml:"maxAttempts"`
+	// Lease is the maximum time a job can run, after which it's picked up again by another worker
+	Lease       Duration `json:"lease" yaml:"lease" toml:"lease"`
+	BackoffBase Duration `json:"backoffBase" yaml:"backoffBase" toml:"backoffBase"`
+	BackoffMax  Duration `json:"backoffMax" yaml:"backoffMax" toml:"backoffMax"`
+	Table       string   `json:"table" yaml:"table" toml:"table"`
+}
+
+// IdempotencyConfig has the configurations of the Idempotency-Key support of the HTTP APIs
+type IdempotencyConfig struct {
+	// TTL is the duration for which the response of a request is stored
+	TTL Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+	// LockTimeout is the duration after which an in progress request is considered abandoned
+	LockTimeout Duration `json:"lockTimeout" yaml:"lockTimeout" toml:"lockTimeout"`
+	Table       string   `json:"table" yaml:"table" toml:"table"`
+}
+
+// MailerConfig has the configurations of the mailer used to send emails
+type MailerConfig struct {
+	// Type is one of MailerSMTP, MailerFile or MailerLog
+	Type string `json:"type" yaml:"type" toml:"type"`
+	// From is the sender of all the emails, e.g. "goapp <noreply@example.com>"
+	From         string `json:"from" yaml:"from" toml:"from"`
+	SMTPHost     string `json:"smtpHost" yaml:"smtpHost" toml:"smtpHost"`
+	SMTPPort     uint16 `json:"smtpPort" yaml:"smtpPort" toml:"smtpPort"`
+	SMTPUsername string `json:"smtpUsername" yaml:"smtpUsername" toml:"smtpUsername"`
+	SMTPPassword string `json:"smtpPassword" yaml:"smtpPassword" toml:"smtpPassword"`
+	// FilePath is the file to which the emails are appended, for MailerFile
+	FilePath string `json:"filePath" yaml:"filePath" toml:"filePath"`
+}
+
+// VerificationConfig has the configurations of the email verification of users
+type VerificationConfig struct {
+	// Secret is the key used to sign the verification tokens, it is required in staging & production
+	Secret   string   `json:"secret" yaml:"secret" toml:"secret"`
+	TokenTTL Duration `json:"tokenTTL" yaml:"tokenTTL" toml:"tokenTTL"`
+	// BaseURL is the public URL of the app, used for the verification links
+	BaseURL string `json:"baseURL" yaml:"baseURL" toml:"baseURL"`
+	Table   string `json:"table" yaml:"table" toml:"table"`
+}
+
+// LifecycleConfig has the configurations related to startup & shutdown of the app
+type LifecycleConfig struct {
+	HealthPort uint16 `json:"healthPort" yaml:"healthPort" toml:"healthPort"`
+	// ShutdownGracePeriod is the maximum time allowed for the app to shutdown gracefully
+	ShutdownGracePeriod Duration `json:"shutdownGracePeriod" yaml:"shutdownGracePeriod" toml:"shutdownGracePeriod"`
+	// ProbeInterval is the interval at which the probes (e.g. Kubernetes) check the app
+	ProbeInterval Duration `json:"probeInterval" yaml:"probeInterval" toml:"probeInterval"`
+}
+
 // Configs struct handles all dependencies required for handling configurations
 type Configs struct {
-	Environment env
-	AppName     string
-	AppVersion  string
+	Environment      env                `json:"environment" yaml:"environment" toml:"environment"`
+	AppName          string             `json:"appName" yaml:"appName" toml:"appName"`
+	AppVersion       string             `json:"appVersion" yaml:"appVersion" toml:"appVersion"`
+	StoreType        string             `json:"store" yaml:"store" toml:"store"`
+	Migrate          bool               `json:"migrateOnStart" yaml:"migrateOnStart" toml:"migrateOnStart"`
+	HTTPServer       HTTPConfig         `json:"http" yaml:"http" toml:"http"`
+	GRPCServer       GRPCConfig         `json:"grpc" yaml:"grpc" toml:"grpc"`
+	PostgresDB       PostgresConfig     `json:"postgres" yaml:"postgres" toml:"postgres"`
+	APMOpts          APMConfig          `json:"apm" yaml:"apm" toml:"apm"`
+	JobsOpts         JobsConfig         `json:"jobs" yaml:"jobs" toml:"jobs"`
+	IdempotencyOpts  IdempotencyConfig  `json:"idempotency" yaml:"idempotency" toml:"idempotency"`
+	MailerOpts       MailerConfig       `json:"mailer" yaml:"mailer" toml:"mailer"`
+	VerificationOpts VerificationConfig `json:"verification" yaml:"verification" toml:"verification"`
+	LifecycleOpts    LifecycleConfig    `json:"lifecycle" yaml:"lifecycle" toml:"lifecycle"`
+
+	// args are the command line arguments remaining after parsing the flags
+	args []string
 }
 
 // HTTP returns the configuration required for HTTP package
 func (cfg *Configs) HTTP() (*http.Config, error) {
 	return &http.Config{
-		EnableAccessLog:   (cfg.Environment == EnvLocal) || (cfg.Environment == EnvTest),
-		TemplatesBasePath: strings.TrimSpace(os.Getenv("TEMPLATES_BASEPATH")),
-		Port:              8080,
-		ReadTimeout:       time.Second * 5,
-		WriteTimeout:      time.Second * 5,
-		DialTimeout:       time.Second * 3,
+		EnableAccessLog:   cfg.HTTPServer.AccessLog,
+		TemplatesBasePath: cfg.HTTPServer.TemplatesBasePath,
+		Host:              cfg.HTTPServer.Host,
+		Port:              cfg.HTTPServer.Port,
+		ReadTimeout:       cfg.HTTPServer.ReadTimeout.Duration(),
+		WriteTimeout:      cfg.HTTPServer.WriteTimeout.Duration(),
+		DialTimeout:       cfg.HTTPServer.DialTimeout.Duration(),
 	}, nil
 }
 
+// GRPC returns the configuration required for gRPC package
+func (cfg *Configs) GRPC() *grpc.Config {
+	return &grpc.Config{
+		Host: cfg.GRPCServer.Host,
+		Port: cfg.GRPCServer.Port,
+		// reflection exposes the complete API surface, so it's not enabled in production
+		Reflection: cfg.Environment != EnvProduction,
+	}
+}
+
 func (cfg *Configs) Postgres() *postgres.Config {
+	pcfg := cfg.PostgresDB
 	return &postgres.Config{
-		Host:   os.Getenv("POSTGRES_HOST"),
-		Port:   os.Getenv("POSTGRES_PORT"),
+		Host:   pcfg.Host,
+		Port:   pcfg.Port,
 		Driver: "postgres",
 
-		StoreName: os.Getenv("POSTGRES_STORENAME"),
-		Username:  os.Getenv("POSTGRES_USERNAME"),
-		Password:  os.Getenv("POSTGRES_PASSWORD"),
+		StoreName: pcfg.StoreName,
+		Username:  pcfg.Username,
+		Password:  pcfg.Password,
+		SSLMode:   pcfg.SSLMode,
+
+		ConnPoolSize: pcfg.ConnPoolSize,
+		ReadTimeout:  pcfg.ReadTimeout.Duration(),
+		WriteTimeout: pcfg.WriteTimeout.Duration(),
+		IdleTimeout:  pcfg.IdleTimeout.Duration(),
+		DialTimeout:  pcfg.DialTimeout.Duration(),
+	}
+}
+
+// APM returns the options required for initializing APM
+func (cfg *Configs) APM() *apm.Options {
+	return &apm.Options{
+		Debug:                cfg.APMOpts.Debug,
+		Environment:          cfg.Environment.String(),
+		ServiceName:          cfg.AppName,
+		ServiceVersion:       cfg.AppVersion,
+		TracesSampleRate:     cfg.APMOpts.TracesSampleRate,
+		CollectorURL:         cfg.APMOpts.CollectorURL,
+		PrometheusScrapePort: cfg.APMOpts.PrometheusScrapePort,
+		UseStdOut:            cfg.APMOpts.UseStdOut,
+	}
+}
 
-		ConnPoolSize: 24,
-		ReadTimeout:  time.Second * 3,
-		WriteTimeout: time.Second * 6,
-		IdleTimeout:  time.Minute,
-		DialTimeout:  time.Second * 3,
+// Jobs returns the options required for the background job queue
+func (cfg *Configs) Jobs() jobqueue.Options {
+	return jobqueue.Options{
+		Workers:      cfg.JobsOpts.Workers,
+		PollInterval: cfg.JobsOpts.PollInterval.Duration(),
+		MaxAttempts:  cfg.JobsOpts.MaxAttempts,
+		Lease:        cfg.JobsOpts.Lease.Duration(),
+		BackoffBase:  cfg.JobsOpts.BackoffBase.Duration(),
+		BackoffMax:   cfg.JobsOpts.BackoffMax.Duration(),
 	}
 }
 
+func (cfg *Configs) JobsPostgresTable() string {
+	return cfg.JobsOpts.Table
+}
+
+// Idempotency returns the options required for the Idempotency-Key support of the HTTP APIs
+func (cfg *Configs) Idempotency() idempotency.Options {
+	return idempotency.Options{
+		TTL:         cfg.IdempotencyOpts.TTL.Duration(),
+		LockTimeout: cfg.IdempotencyOpts.LockTimeout.Duration(),
+	}
+}
+
+func (cfg *Configs) IdempotencyPostgresTable() string {
+	return cfg.IdempotencyOpts.Table
+}
+
+// MailerType returns the type of mailer to be used, MailerSMTP, MailerFile or MailerLog
+func (cfg *Configs) MailerType() string {
+	return cfg.MailerOpts.Type
+}
+
+// MailerFrom is the sender of all the emails
+func (cfg *Configs) MailerFrom() string {
+	return cfg.MailerOpts.From
+}
+
+// SMTP returns the configuration required for the SMTP mailer
+func (cfg *Configs) SMTP() mailer.SMTPConfig {
+	return mailer.SMTPConfig{
+		Host:     cfg.MailerOpts.SMTPHost,
+		Port:     cfg.MailerOpts.SMTPPort,
+		Username: cfg.MailerOpts.SMTPUsername,
+		Password: cfg.MailerOpts.SMTPPassword,
+		From:     cfg.MailerOpts.From,
+	}
+}
+
+// MailerFilePath is the file to which the file mailer appends the emails
+func (cfg *Configs) MailerFilePath() string {
+	return cfg.MailerOpts.FilePath
+}
+
+// Verification returns the options required for the email verification of users
+func (cfg *Configs) Verification() verification.Options {
+	return verification.Options{
+		Secret:   cfg.VerificationOpts.Secret,
+		TokenTTL: cfg.VerificationOpts.TokenTTL.Duration(),
+		BaseURL:  cfg.VerificationOpts.BaseURL,
+	}
+}
+
+func (cfg *Configs) VerificationPostgresTable() string {
+	return cfg.VerificationOpts.Table
+}
+
 func (cfg *Configs) UserPostgresTable() string {
-	return "users"
+	return cfg.PostgresDB.UsersTable
 }
 
 func (cfg *Configs) UserNotesPostgresTable() string {
-	return "user_notes"
-}
-
-func loadEnv() env {
-	switch env(os.Getenv("ENV")) {
-	case EnvLocal:
-		return EnvLocal
-	case EnvTest:
-		return EnvTest
-	case EnvStaging:
-		return EnvProduction
-	case EnvProduction:
-		return EnvProduction
+	return cfg.PostgresDB.UserNotesTable
+}
+
+// Store returns the type of datastore to be used, StorePostgres (default) or StoreMemory.
+// The in-memory store does not persist anything and is meant for local development & tests
+func (cfg *Configs) Store() string {
+	return cfg.StoreType
+}
+
+// MigrateOnStart returns true if the pending DB migrations are to be applied when the app starts
+func (cfg *Configs) MigrateOnStart() bool {
+	return cfg.Migrate
+}
+
+// HealthPort is the port on which the health responder listens
+func (cfg *Configs) HealthPort() uint16 {
+	return cfg.LifecycleOpts.HealthPort
+}
+
+// ShutdownGracePeriod is the maximum time allowed for the app to shutdown gracefully
+func (cfg *Configs) ShutdownGracePeriod() time.Duration {
+	return cfg.LifecycleOpts.ShutdownGracePeriod.Duration()
+}
+
+// ProbeInterval is the interval at which the probes (e.g. Kubernetes) check the app
+func (cfg *Configs) ProbeInterval() time.Duration {
+	return cfg.LifecycleOpts.ProbeInterval.Duration()
+}
+
+// Args returns the command line arguments remaining after parsing all the flags, e.g. subcommands
+func (cfg *Configs) Args() []string {
+	return cfg.args
+}
+
+// Validate validates all the configurations and returns all the errors found, joined
+func (cfg *Configs) Validate() error {
+	errs := make([]error, 0)
+	invalid := func(format string, args ...any) {
+		errs = append(errs, errors.Validationf(format, args...))
+	}
+
+	err := cfg.Environment.Validate()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if strings.TrimSpace(cfg.AppName) == "" {
+		invalid("appName cannot be empty")
+	}
+
+	switch cfg.StoreType {
+	case StorePostgres, StoreMemory:
+	default:
+		invalid("store should be one of '%s', '%s', got '%s'", StorePostgres, StoreMemory, cfg.StoreType)
+	}
+
+	hcfg := cfg.HTTPServer
+	if hcfg.Port == 0 {
+		invalid("http.port cannot be 0")
+	}
+	if hcfg.TemplatesBasePath == "" {
+		invalid("http.templatesBasePath cannot be empty")
+	}
+	for name, value := range map[string]Duration{
+		"http.readTimeout":  hcfg.ReadTimeout,
+		"http.writeTimeout": hcfg.WriteTimeout,
+		"http.dialTimeout":  hcfg.DialTimeout,
+	} {
+		if value <= 0 {
+			invalid("%s should be greater than 0", name)
+		}
+	}
+
+	gcfg := cfg.GRPCServer
+	if gcfg.Port == 0 {
+		invalid("grpc.port cannot be 0")
+	} else if gcfg.Port == hcfg.Port {
+		invalid("grpc.port cannot be the same as http.port")
+	}
+
+	if cfg.StoreType == StorePostgres {
+		pcfg := cfg.PostgresDB
+		for name, value := range map[string]string{
+			"postgres.host":           pcfg.Host,
+			"postgres.port":           pcfg.Port,
+			"postgres.storeName":      pcfg.StoreName,
+			"postgres.username":       pcfg.Username,
+			"postgres.usersTable":     pcfg.UsersTable,
+			"postgres.userNotesTable": pcfg.UserNotesTable,
+		} {
+			if strings.TrimSpace(value) == "" {
+				invalid("%s cannot be empty", name)
+			}
+		}
+		if pcfg.ConnPoolSize == 0 {
+			invalid("postgres.connPoolSize cannot be 0")
+		}
+		for name, value := range map[string]Duration{
+			"postgres.readTimeout":  pcfg.ReadTimeout,
+			"postgres.writeTimeout": pcfg.WriteTimeout,
+			"postgres.idleTimeout":  pcfg.IdleTimeout,
+			"postgres.dialTimeout":  pcfg.DialTimeout,
+		} {
+			if value <= 0 {
+				invalid("%s should be greater than 0", name)
+			}
+		}
+	}
+
+	jcfg := cfg.JobsOpts
+	if jcfg.Workers <= 0 {
+		invalid("jobs.workers should be greater than 0")
+	}
+	if jcfg.MaxAttempts <= 0 {
+		invalid("jobs.maxAttempts should be greater than 0")
+	}
+	for name, value := range map[string]Duration{
+		"jobs.pollInterval": jcfg.PollInterval,
+		"jobs.lease":        jcfg.Lease,
+		"jobs.backoffBase":  jcfg.BackoffBase,
+		"jobs.backoffMax":   jcfg.BackoffMax,
+	} {
+		if value <= 0 {
+			invalid("%s should be greater than 0", name)
+		}
+	}
+	if jcfg.BackoffBase > jcfg.BackoffMax {
+		invalid("jobs.backoffBase cannot be greater than jobs.backoffMax")
+	}
+	if cfg.StoreType == StorePostgres && strings.TrimSpace(jcfg.Table) == "" {
+		invalid("jobs.table cannot be empty")
+	}
+
+	icfg := cfg.IdempotencyOpts
+	for name, value := range map[string]Duration{
+		"idempotency.ttl":         icfg.TTL,
+		"idempotency.lockTimeout": icfg.LockTimeout,
+	} {
+		if value <= 0 {
+			invalid("%s should be greater than 0", name)
+		}
+	}
+	if cfg.StoreType == StorePostgres && strings.TrimSpace(icfg.Table) == "" {
+		invalid("idempotency.table cannot be empty")
+	}
+
+	mcfg := cfg.MailerOpts
+	switch mcfg.Type {
+	case MailerSMTP:
+		if strings.TrimSpace(mcfg.SMTPHost) == "" {
+			invalid("mailer.smtpHost cannot be empty")
+		}
+		if mcfg.SMTPPort == 0 {
+			invalid("mailer.smtpPort cannot be 0")
+		}
+	case MailerFile:
+		if strings.TrimSpace(mcfg.FilePath) == "" {
+			invalid("mailer.filePath cannot be empty")
+		}
+	case MailerLog:
 	default:
-		return EnvLocal
+		invalid("mailer.type should be one of '%s', '%s', '%s', got '%s'", MailerSMTP, MailerFile, MailerLog, mcfg.Type)
 	}
+	if _, err := mail.ParseAddress(mcfg.From); err != nil {
+		invalid("mailer.from should be a valid address, got '%s'", mcfg.From)
+	}
+
+	vcfg := cfg.VerificationOpts
+	if vcfg.Secret == "" && cfg.Environment != EnvLocal && cfg.Environment != EnvTest {
+		invalid("verification.secret cannot be empty")
+	}
+	if vcfg.TokenTTL <= 0 {
+		invalid("verification.tokenTTL should be greater than 0")
+	}
+	if burl, err := url.Parse(vcfg.BaseURL); err != nil || burl.Scheme == "" || burl.Host == "" {
+		invalid("verification.baseURL should be an absolute URL, got '%s'", vcfg.BaseURL)
+	}
+	if cfg.StoreType == StorePostgres && strings.TrimSpace(vcfg.Table) == "" {
+		invalid("verification.table cannot be empty")
+	}
+
+	if rate := cfg.APMOpts.TracesSampleRate; rate < 0 || rate > 1 {
+		invalid("apm.tracesSampleRate should be between 0 and 1, got %v", rate)
+	}
+
+	lcfg := cfg.LifecycleOpts
+	if lcfg.HealthPort == 0 {
+		invalid("lifecycle.healthPort cannot be 0")
+	} else if lcfg.HealthPort == hcfg.Port {
+		invalid("lifecycle.healthPort cannot be the same as http.port")
+	} else if lcfg.HealthPort == gcfg.Port {
+		invalid("lifecycle.healthPort cannot be the same as grpc.port")
+	}
+	if lcfg.ShutdownGracePeriod <= 0 {
+		invalid("lifecycle.shutdownGracePeriod should be greater than 0")
+	}
+	if lcfg.ProbeInterval <= 0 {
+		invalid("lifecycle.probeInterval should be greater than 0")
+	} else if lcfg.ProbeInterval >= lcfg.ShutdownGracePeriod {
+		invalid("lifecycle.probeInterval should be less than lifecycle.shutdownGracePeriod")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.ValidationErrf(errors.Join(errs...), "%d invalid configuration(s)", len(errs))
 }
 
-// New returns an instance of Config with all the required dependencies initialized
-func New() (*Configs, error) {
+func defaults() *Configs {
 	return &Configs{
-		Environment: loadEnv(),
-		AppName:     os.Getenv("APP_NAME"),
-		AppVersion:  os.Getenv("APP_VERSION"),
-	}, nil
+		Environment: EnvLocal,
+		AppName:     "goapp",
+		StoreType:   StorePostgres,
+		HTTPServer: HTTPConfig{
+			Port:              8080,
+			ReadTimeout:       Duration(time.Second * 5),
+			WriteTimeout:      Duration(time.Second * 5),
+			DialTimeout:       Duration(time.Second * 3),
+			TemplatesBasePath: "cmd/server/http/web/templates",
+		},
+		GRPCServer: GRPCConfig{
+			Port: 8090,
+		},
+		PostgresDB: PostgresConfig{
+			ConnPoolSize:   24,
+			ReadTimeout:    Duration(time.Second * 3),
+			WriteTimeout:   Duration(time.Second * 6),
+			IdleTimeout:    Duration(time.Minute),
+			DialTimeout:    Duration(time.Second * 3),
+			UsersTable:     "users",
+			UserNotesTable: "user_notes",
+		},
+		APMOpts: APMConfig{
+			TracesSampleRate:     0.5,
+			PrometheusScrapePort: 9090,
+		},
+		JobsOpts: JobsConfig{
+			Workers:      4,
+			PollInterval: Duration(time.Second),
+			MaxAttempts:  5,
+			Lease:        Duration(time.Minute * 5),
+			BackoffBase:  Duration(time.Second),
+			BackoffMax:   Duration(time.Minute * 10),
+			Table:        "jobs",
+		},
+		IdempotencyOpts: IdempotencyConfig{
+			TTL:         Duration(time.Hour * 24),
+			LockTimeout: Duration(time.Minute),
+			Table:       "idempotency_keys",
+		},
+		MailerOpts: MailerConfig{
+			Type:     MailerLog,
+			From:     "goapp <noreply@localhost>",
+			SMTPPort: 587,
+		},
+		VerificationOpts: VerificationConfig{
+			TokenTTL: Duration(time.Hour * 24),
+			BaseURL:  "http://localhost:8080",
+			Table:    "user_verification_tokens",
+		},
+		LifecycleOpts: LifecycleConfig{
+			HealthPort:          2000,
+			ShutdownGracePeriod: Duration(time.Minute),
+			ProbeInterval:       Duration(time.Second * 3),
+		},
+	}
 }
@@ -0,0 +1,375 @@
+package configs
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/naughtygopher/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is a configuration file, the format of which is identified using the file extension
+type configFile struct {
+	path string
+	ext  string
+	raw  []byte
+}
+
+func readConfigFile(path string) (*configFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading config file %s", path)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".json", ".toml":
+	default:
+		return nil, errors.Validationf("unsupported config file format '%s', should be one of .yaml, .yml, .json, .toml", ext)
+	}
+
+	return &configFile{path: path, ext: ext, raw: raw}, nil
+}
+
+// decode decodes the file into target. If strict, unknown fields in the file are not allowed
+func (cf *configFile) decode(target any, strict bool) error {
+	var err error
+	switch cf.ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(cf.raw))
+		dec.KnownFields(strict)
+		err = dec.Decode(target)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(cf.raw))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+		err = dec.Decode(target)
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(cf.raw), target)
+		if err == nil && strict && len(md.Undecoded()) > 0 {
+			return errors.InputBodyf("invalid config file %s, unknown fields: %v", cf.path, md.Undecoded())
+		}
+	}
+
+	if err != nil {
+		return errors.InputBodyErrf(err, "invalid config file %s", cf.path)
+	}
+
+	return nil
+}
+
+// environment returns the environment set in the config file, if any
+func (cf *configFile) environment() (env, error) {
+	peek := struct {
+		Environment env `json:"environment" yaml:"environment" toml:"environment"`
+	}{}
+	err := cf.decode(&peek, false)
+	if err != nil {
+		return "", err
+	}
+	return peek.Environment, nil
+}
+
+// envLoader reads environment variables into the configuration, collecting all the parsing errors
+type envLoader struct {
+	errs []error
+}
+
+func (el *envLoader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(value), true
+}
+
+func (el *envLoader) string(key string, target *string) {
+	if value, ok := el.lookup(key); ok {
+		*target = value
+	}
+}
+
+func (el *envLoader) bool(key string, target *bool) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be a boolean, got '%s'", key, value))
+		return
+	}
+	*target = parsed
+}
+
+func (el *envLoader) port(key string, target *uint16) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be a valid port number, got '%s'", key, value))
+		return
+	}
+	*target = uint16(parsed)
+}
+
+func (el *envLoader) uint(key string, target *uint) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be a positive integer, got '%s'", key, value))
+		return
+	}
+	*target = uint(parsed)
+}
+
+func (el *envLoader) int(key string, target *int) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be an integer, got '%s'", key, value))
+		return
+	}
+	*target = parsed
+}
+
+func (el *envLoader) float(key string, target *float64) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be a number, got '%s'", key, value))
+		return
+	}
+	*target = parsed
+}
+
+func (el *envLoader) duration(key string, target *Duration) {
+	value, ok := el.lookup(key)
+	if !ok {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		el.errs = append(el.errs, errors.Validationf("%s should be a duration (e.g. 5s), got '%s'", key, value))
+		return
+	}
+	*target = Duration(parsed)
+}
+
+func loadEnvVars(cfg *Configs) error {
+	el := &envLoader{}
+	el.string("APP_NAME", &cfg.AppName)
+	el.string("APP_VERSION", &cfg.AppVersion)
+	el.string("STORE", &cfg.StoreType)
+	el.bool("MIGRATE_ON_START", &cfg.Migrate)
+
+	el.string("HTTP_HOST", &cfg.HTTPServer.Host)
+	el.port("HTTP_PORT", &cfg.HTTPServer.Port)
+	el.string("TEMPLATES_BASEPATH", &cfg.HTTPServer.TemplatesBasePath)
+
+	el.string("GRPC_HOST", &cfg.GRPCServer.Host)
+	el.port("GRPC_PORT", &cfg.GRPCServer.Port)
+
+	el.string("POSTGRES_HOST", &cfg.PostgresDB.Host)
+	el.string("POSTGRES_PORT", &cfg.PostgresDB.Port)
+	el.string("POSTGRES_STORENAME", &cfg.PostgresDB.StoreName)
+	el.string("POSTGRES_USERNAME", &cfg.PostgresDB.Username)
+	el.string("POSTGRES_PASSWORD", &cfg.PostgresDB.Password)
+	el.string("POSTGRES_SSLMODE", &cfg.PostgresDB.SSLMode)
+	el.uint("POSTGRES_POOL_SIZE", &cfg.PostgresDB.ConnPoolSize)
+
+	el.string("APM_COLLECTOR_URL", &cfg.APMOpts.CollectorURL)
+	el.float("APM_TRACES_SAMPLE_RATE", &cfg.APMOpts.TracesSampleRate)
+	el.port("APM_PROMETHEUS_PORT", &cfg.APMOpts.PrometheusScrapePort)
+
+	el.int("JOBS_WORKERS", &cfg.JobsOpts.Workers)
+	el.int("JOBS_MAX_ATTEMPTS", &cfg.JobsOpts.MaxAttempts)
+	el.duration("JOBS_POLL_INTERVAL", &cfg.JobsOpts.PollInterval)
+
+	el.duration("IDEMPOTENCY_TTL", &cfg.IdempotencyOpts.TTL)
+
+	el.string("MAILER_TYPE", &cfg.MailerOpts.Type)
+	el.string("MAILER_FROM", &cfg.MailerOpts.From)
+	el.string("SMTP_HOST", &cfg.MailerOpts.SMTPHost)
+	el.port("SMTP_PORT", &cfg.MailerOpts.SMTPPort)
+	el.string("SMTP_USERNAME", &cfg.MailerOpts.SMTPUsername)
+	el.string("SMTP_PASSWORD", &cfg.MailerOpts.SMTPPassword)
+
+	el.string("VERIFICATION_SECRET", &cfg.VerificationOpts.Secret)
+	el.string("VERIFICATION_BASE_URL", &cfg.VerificationOpts.BaseURL)
+
+	el.port("HEALTH_PORT", &cfg.LifecycleOpts.HealthPort)
+	el.duration("SHUTDOWN_GRACE_PERIOD", &cfg.LifecycleOpts.ShutdownGracePeriod)
+	el.duration("PROBE_INTERVAL", &cfg.LifecycleOpts.ProbeInterval)
+
+	return errors.Join(el.errs...)
+}
+
+type flags struct {
+	set        *flag.FlagSet
+	configFile string
+	env        string
+	store      string
+	migrate    bool
+	httpPort   uint
+	grpcPort   uint
+	healthPort uint
+}
+
+func parseFlags(args []string) (*flags, error) {
+	fls := &flags{
+		set: flag.NewFlagSet("goapp", flag.ContinueOnError),
+	}
+	fls.set.StringVar(&fls.configFile, "config", "", "path to the config file (.yaml, .yml, .json or .toml), env CONFIG_FILE")
+	fls.set.StringVar(&fls.env, "env", "", "environment of the app, env ENV")
+	fls.set.StringVar(&fls.store, "store", "", "datastore to use (postgres or memory), env STORE")
+	fls.set.BoolVar(&fls.migrate, "migrate-on-start", false, "apply pending migrations on start, env MIGRATE_ON_START")
+	fls.set.UintVar(&fls.httpPort, "http-port", 0, "port of the HTTP server, env HTTP_PORT")
+	fls.set.UintVar(&fls.grpcPort, "grpc-port", 0, "port of the gRPC server, env GRPC_PORT")
+	fls.set.UintVar(&fls.healthPort, "health-port", 0, "port of the health responder, env HEALTH_PORT")
+
+	err := fls.set.Parse(args)
+	if err != nil {
+		return nil, errors.InputBodyErr(err, "invalid command line flags")
+	}
+
+	return fls, nil
+}
+
+// apply sets only the flags which were explicitly provided, on to the config
+func (fls *flags) apply(cfg *Configs) error {
+	errs := make([]error, 0)
+	fls.set.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "store":
+			cfg.StoreType = fls.store
+		case "migrate-on-start":
+			cfg.Migrate = fls.migrate
+		case "http-port":
+			if fls.httpPort > 65535 {
+				errs = append(errs, errors.Validationf("-http-port should be a valid port number, got %d", fls.httpPort))
+				return
+			}
+			cfg.HTTPServer.Port = uint16(fls.httpPort)
+		case "grpc-port":
+			if fls.grpcPort > 65535 {
+				errs = append(errs, errors.Validationf("-grpc-port should be a valid port number, got %d", fls.grpcPort))
+				return
+			}
+			cfg.GRPCServer.Port = uint16(fls.grpcPort)
+		case "health-port":
+			if fls.healthPort > 65535 {
+				errs = append(errs, errors.Validationf("-health-port should be a valid port number, got %d", fls.healthPort))
+				return
+			}
+			cfg.LifecycleOpts.HealthPort = uint16(fls.healthPort)
+		}
+	})
+	cfg.args = fls.set.Args()
+
+	return errors.Join(errs...)
+}
+
+// resolveEnv returns the environment of the app, which decides the profile to be loaded.
+// In the order of precedence: -env flag, ENV env variable, config file and finally EnvLocal
+func resolveEnv(fls *flags, cfile *configFile) (env, error) {
+	if fls.env != "" {
+		return env(fls.env), nil
+	}
+
+	if value := strings.TrimSpace(os.Getenv("ENV")); value != "" {
+		return env(value), nil
+	}
+
+	if cfile != nil {
+		environment, err := cfile.environment()
+		if err != nil {
+			return "", err
+		}
+		if environment != "" {
+			return environment, nil
+		}
+	}
+
+	return EnvLocal, nil
+}
+
+// New returns an instance of Config with all the required dependencies initialized.
+// The configurations are loaded in the following order, each overriding the previous:
+// environment profile, config file (-config flag or CONFIG_FILE env), environment variables,
+// command line flags. The environment (-env flag, ENV env or the config file) must be one of
+// the known environments. args are the command line arguments (without the program name),
+// and all the arguments remaining after the flags are available through Configs.Args()
+func New(args ...string) (*Configs, error) {
+	fls, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfile *configFile
+	path := fls.configFile
+	if path == "" {
+		path = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	}
+	if path != "" {
+		cfile, err = readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	environment, err := resolveEnv(fls, cfile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := newProfile(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfile != nil {
+		err = cfile.decode(cfg, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the environment resolved decides the profile, and cannot be overridden by the file
+	cfg.Environment = environment
+
+	err = errors.Join(loadEnvVars(cfg), fls.apply(cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid configuration")
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,80 @@
+package configs
+
+import (
+	"net/url"
+)
+
+const redacted = "[redacted]"
+
+// developmentSecret is the secret used to sign tokens in local & test environments, so that
+// they work without any configuration. Staging & production require a secret to be configured
+const developmentSecret = "goapp-development-secret"
+
+// profiles are the default configurations of each environment, applied on top of the
+// common defaults. Config file, env variables and flags can further override them
+var profiles = map[env]func(cfg *Configs){
+	EnvLocal: func(cfg *Configs) {
+		cfg.HTTPServer.AccessLog = true
+		cfg.APMOpts.UseStdOut = true
+		cfg.APMOpts.Debug = true
+		cfg.APMOpts.TracesSampleRate = 1
+		cfg.PostgresDB.ConnPoolSize = 8
+		cfg.VerificationOpts.Secret = developmentSecret
+	},
+	EnvTest: func(cfg *Configs) {
+		cfg.HTTPServer.AccessLog = true
+		cfg.APMOpts.TracesSampleRate = 1
+		cfg.PostgresDB.ConnPoolSize = 8
+		cfg.VerificationOpts.Secret = developmentSecret
+	},
+	EnvStaging: func(cfg *Configs) {
+		cfg.APMOpts.TracesSampleRate = 0.5
+		cfg.PostgresDB.ConnPoolSize = 16
+	},
+	EnvProduction: func(cfg *Configs) {
+		cfg.APMOpts.TracesSampleRate = 0.1
+		cfg.PostgresDB.ConnPoolSize = 24
+	},
+}
+
+// newProfile returns the default configuration for the given environment
+func newProfile(environment env) (*Configs, error) {
+	err := environment.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := defaults()
+	cfg.Environment = environment
+	profiles[environment](cfg)
+
+	return cfg, nil
+}
+
+// Redacted returns a copy of the configuration with all the secrets redacted,
+// so that it is safe to be logged or exposed
+func (cfg *Configs) Redacted() *Configs {
+	rcfg := *cfg
+	rcfg.args = nil
+	if rcfg.PostgresDB.Password != "" {
+		rcfg.PostgresDB.Password = redacted
+	}
+	if rcfg.MailerOpts.SMTPPassword != "" {
+		rcfg.MailerOpts.SMTPPassword = redacted
+	}
+	if rcfg.VerificationOpts.Secret != "" {
+		rcfg.VerificationOpts.Secret = redacted
+	}
+
+	if rcfg.APMOpts.CollectorURL != "" {
+		curl, err := url.Parse(rcfg.APMOpts.CollectorURL)
+		if err != nil {
+			// an unparsable URL could have credentials in any form
+			rcfg.APMOpts.CollectorURL = redacted
+		} else {
+			rcfg.APMOpts.CollectorURL = curl.Redacted()
+		}
+	}
+
+	return &rcfg
+}
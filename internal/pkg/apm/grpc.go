@@ -1,48 +1,33 @@
 package apm
 
 import (
-	"context"
+	"strings"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/stats"
 )
 
-type grpcHandler struct {
-	stats.Handler
-	customHandler func(ctx context.Context, rs stats.RPCStats)
-}
-
-func (gh *grpcHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
-	gh.customHandler(ctx, rs)
-}
-
-func OtelGRPCNewServerHandler(ignoredMethods ...string) stats.Handler {
+// OtelGRPCNewServerHandler returns a gRPC stats handler which records traces & metrics of all
+// the RPCs, except the ignored ones. Each of ignored can either be a service name
+// (e.g. grpc.health.v1.Health) or a full method name (e.g. /grpc.health.v1.Health/Check)
+func OtelGRPCNewServerHandler(ignored ...string) stats.Handler {
 	checkList := map[string]struct{}{}
-	for _, m := range ignoredMethods {
+	for _, m := range ignored {
 		checkList[m] = struct{}{}
 	}
 
-	handler := otelgrpc.NewServerHandler(
+	return otelgrpc.NewServerHandler(
 		otelgrpc.WithTracerProvider(Global().GetTracerProvider()),
 		otelgrpc.WithMeterProvider(Global().GetMeterProvider()),
+		otelgrpc.WithFilter(func(info *stats.RPCTagInfo) bool {
+			if _, skip := checkList[info.FullMethodName]; skip {
+				return false
+			}
+
+			// full method name is of the format /<service name>/<method name>
+			service, _, _ := strings.Cut(strings.TrimPrefix(info.FullMethodName, "/"), "/")
+			_, skip := checkList[service]
+			return !skip
+		}),
 	)
-
-	gh := &grpcHandler{}
-	gh.Handler = handler
-	gh.customHandler = func(ctx context.Context, rs stats.RPCStats) {
-		methodName, ok := grpc.Method(ctx)
-		if !ok {
-			return
-		}
-
-		_, skip := checkList[methodName]
-		if !skip {
-			return
-		}
-
-		handler.HandleRPC(ctx, rs)
-	}
-
-	return gh
 }
@@ -0,0 +1,263 @@
+// Package idempotency makes HTTP requests idempotent using the Idempotency-Key header. The response
+// of the first request with a key is stored, and any retry with the same key is responded with
+// the stored response instead of processing the request again
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+)
+
+const (
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set in the response when it's the stored response of a previous request
+	HeaderReplayed = "Idempotent-Replayed"
+	maxKeyLength   = 255
+)
+
+var (
+	ErrInProgress = errors.Duplicate("a request with the same Idempotency-Key is in progress")
+	ErrMismatch   = errors.Validation("Idempotency-Key was already used with a different request")
+)
+
+// Response is the stored response of a request
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record is the state of an Idempotency-Key
+type Record struct {
+	Key string
+	// Fingerprint identifies the request, so that a key reused for a different request is rejected
+	Fingerprint string
+	// Response is nil while the request is in progress
+	Response  *Response
+	ExpiresAt time.Time
+}
+
+type store interface {
+	// Acquire saves an in progress record for the key which expires after lockTimeout, if there's
+	// no record or if the existing record has expired. It returns nil if acquired, and the
+	// existing record otherwise
+	Acquire(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error)
+	// Complete saves the response of an acquired key, which expires after ttl
+	Complete(ctx context.Context, key string, fingerprint string, resp *Response, ttl time.Duration) error
+	// Release deletes an acquired key which is still in progress, so that the request can be retried
+	Release(ctx context.Context, key string, fingerprint string) error
+	// Purge deletes all the expired records, and returns the number of records deleted
+	Purge(ctx context.Context) (int, error)
+}
+
+type Options struct {
+	// TTL is the duration for which a response is stored
+	TTL time.Duration
+	// LockTimeout is the duration after which an in progress request is considered abandoned
+	// (e.g. the app crashed), and a retry is allowed to process the request again
+	LockTimeout time.Duration
+	// MaxBodySize is the maximum size of a request or response body, larger responses are not stored
+	MaxBodySize int64
+	// PurgeInterval is the interval at which the expired records are deleted
+	PurgeInterval time.Duration
+}
+
+func (opts *Options) withDefaults() {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Hour * 24
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = time.Minute
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1024 * 1024
+	}
+	if opts.PurgeInterval <= 0 {
+		opts.PurgeInterval = time.Hour
+	}
+}
+
+type Idempotency struct {
+	store store
+	opts  Options
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// fingerprint is the hash of the method, path & body of the request
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\n%s\n", r.Method, r.URL.Path)
+	_, _ = hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// respondError responds in the same format as the rest of the HTTP APIs
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg, _ := errors.HTTPStatusCodeMessage(err)
+	if status > 499 {
+		logger.Error(r.Context(), errors.Stacktrace(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"errors": msg,
+		"status": status,
+	})
+}
+
+func replay(w http.ResponseWriter, resp *Response) {
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
+
+// Middleware processes a request with the Idempotency-Key header only once. Retries with the same key
+// are responded with the stored response, while a retry when the first request is still in progress
+// fails with 409. Requests without the header are passed through as is. It has the same signature
+// as a webgo middleware, so it can be used router wide or per route
+func (idm *Idempotency) Middleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	key := strings.TrimSpace(r.Header.Get(HeaderKey))
+	if key == "" {
+		next(w, r)
+		return
+	}
+
+	if len(key) > maxKeyLength {
+		respondError(w, r, errors.InputBodyf("Idempotency-Key cannot be longer than %d characters", maxKeyLength))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, idm.opts.MaxBodySize+1))
+	if err != nil {
+		respondError(w, r, errors.InputBodyErr(err, "failed reading request body"))
+		return
+	}
+	if int64(len(body)) > idm.opts.MaxBodySize {
+		respondError(w, r, errors.InputBodyf("request body cannot be larger than %d bytes", idm.opts.MaxBodySize))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	fprint := fingerprint(r, body)
+	rec, err := idm.store.Acquire(r.Context(), key, fprint, idm.opts.LockTimeout)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if rec != nil {
+		if rec.Fingerprint != fprint {
+			respondError(w, r, ErrMismatch)
+		} else if rec.Response == nil {
+			respondError(w, r, ErrInProgress)
+		} else {
+			replay(w, rec.Response)
+		}
+		return
+	}
+
+	recorder := &responseRecorder{
+		ResponseWriter: w,
+		maxBodySize:    idm.opts.MaxBodySize,
+	}
+
+	// the key is updated even if the request is cancelled, else it'd be locked until the lock timeout
+	ctx := context.WithoutCancel(r.Context())
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		// the handler panicked, or the response cannot be replayed
+		err := idm.store.Release(ctx, key, fprint)
+		if err != nil {
+			logger.Error(ctx, errors.Stacktrace(err))
+		}
+	}()
+
+	next(recorder, r)
+
+	// server errors are not stored, so that the request can be retried
+	if recorder.status() >= http.StatusInternalServerError || recorder.overflow {
+		return
+	}
+
+	err = idm.store.Complete(ctx, key, fprint, recorder.response(), idm.opts.TTL)
+	if err != nil {
+		logger.Error(ctx, errors.Stacktrace(err))
+		return
+	}
+	completed = true
+}
+
+// Start starts deleting the expired records periodically
+func (idm *Idempotency) Start(ctx context.Context) error {
+	idm.wg.Add(1)
+	go func() {
+		defer idm.wg.Done()
+		ticker := time.NewTicker(idm.opts.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-idm.stop:
+				return
+			case <-ticker.C:
+				purged, err := idm.store.Purge(context.WithoutCancel(ctx))
+				if err != nil {
+					logger.Error(ctx, errors.Stacktrace(err))
+				} else if purged > 0 {
+					logger.Info(ctx, fmt.Sprintf("[idempotency] purged %d expired keys", purged))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops deleting the expired records, and waits for an ongoing purge to complete
+func (idm *Idempotency) Shutdown(ctx context.Context) error {
+	close(idm.stop)
+
+	done := make(chan struct{})
+	go func() {
+		idm.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "idempotency purge did not stop in time")
+	}
+}
+
+func New(st store, opts Options) *Idempotency {
+	opts.withDefaults()
+	return &Idempotency{
+		store: st,
+		opts:  opts,
+		stop:  make(chan struct{}),
+	}
+}
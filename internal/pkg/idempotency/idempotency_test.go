@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestServer(handler http.HandlerFunc) *httptest.Server {
+	idm := New(NewMemoryStore(), Options{})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idm.Middleware(w, r, handler)
+	}))
+}
+
+func post(t *testing.T, srv *httptest.Server, path string, key string, body string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, string(raw)
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := atomic.Int32{}
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	})
+	defer srv.Close()
+
+	resp, first := post(t, srv, "/users", "key-1", `{"Email":"a@b.com"}`)
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get(HeaderReplayed) != "" {
+		t.Fatalf("unexpected first response %d, %v", resp.StatusCode, resp.Header)
+	}
+
+	resp, replayed := post(t, srv, "/users", "key-1", `{"Email":"a@b.com"}`)
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get(HeaderReplayed) != "true" {
+		t.Fatalf("expected a replayed response, got %d, %v", resp.StatusCode, resp.Header)
+	}
+	if replayed != first {
+		t.Fatalf("expected the stored response %s, got %s", first, replayed)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected the stored headers, got %v", resp.Header)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected the handler to be called once, got %d", calls.Load())
+	}
+
+	resp, _ = post(t, srv, "/users", "key-1", `{"Email":"c@d.com"}`)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a different request with the same key, got %d", resp.StatusCode)
+	}
+
+	resp, _ = post(t, srv, "/users", "", `{"Email":"a@b.com"}`)
+	if resp.StatusCode != http.StatusCreated || calls.Load() != 2 {
+		t.Fatalf("expected requests without a key to be passed through, got %d", resp.StatusCode)
+	}
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	done := make(chan int)
+	go func() {
+		resp, _ := post(t, srv, "/users", "key-1", "{}")
+		done <- resp.StatusCode
+	}()
+
+	<-started
+	resp, _ := post(t, srv, "/users", "key-1", "{}")
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 while the first request is in progress, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	if status := <-done; status != http.StatusCreated {
+		t.Fatalf("expected the first request to succeed, got %d", status)
+	}
+}
+
+func TestMiddleware_ServerError(t *testing.T) {
+	calls := atomic.Int32{}
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	resp, _ := post(t, srv, "/users", "key-1", "{}")
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+
+	resp, _ = post(t, srv, "/users", "key-1", "{}")
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get(HeaderReplayed) != "" {
+		t.Fatalf("expected the request to be retried after a server error, got %d", resp.StatusCode)
+	}
+}
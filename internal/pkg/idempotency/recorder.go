@@ -0,0 +1,60 @@
+package idempotency
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// responseRecorder writes the response as is, while keeping a copy of it to be stored
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	maxBodySize int64
+	// overflow is set if the response body is larger than maxBodySize, and is not recorded
+	overflow bool
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.statusCode == 0 {
+		rr.statusCode = statusCode
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.statusCode == 0 {
+		rr.statusCode = http.StatusOK
+	}
+
+	if !rr.overflow {
+		if int64(rr.body.Len()+len(b)) > rr.maxBodySize {
+			rr.overflow = true
+			rr.body.Reset()
+		} else {
+			rr.body.Write(b)
+		}
+	}
+
+	return rr.ResponseWriter.Write(b)
+}
+
+// Unwrap is used by http.ResponseController to access the underlying ResponseWriter
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+func (rr *responseRecorder) status() int {
+	if rr.statusCode == 0 {
+		return http.StatusOK
+	}
+	return rr.statusCode
+}
+
+func (rr *responseRecorder) response() *Response {
+	return &Response{
+		Status: rr.status(),
+		Header: rr.Header().Clone(),
+		Body:   bytes.Clone(rr.body.Bytes()),
+	}
+}
@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memstore is an in-memory store, meant for local development & tests. Records are lost on exit
+type memstore struct {
+	mutex   sync.Mutex
+	records map[string]*Record
+}
+
+func (ms *memstore) Acquire(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := time.Now()
+	if rec, ok := ms.records[key]; ok && rec.ExpiresAt.After(now) {
+		crec := *rec
+		return &crec, nil
+	}
+
+	ms.records[key] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(lockTimeout),
+	}
+
+	return nil, nil
+}
+
+func (ms *memstore) Complete(ctx context.Context, key string, fingerprint string, resp *Response, ttl time.Duration) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	rec, ok := ms.records[key]
+	if !ok || rec.Fingerprint != fingerprint {
+		return nil
+	}
+	rec.Response = resp
+	rec.ExpiresAt = time.Now().Add(ttl)
+
+	return nil
+}
+
+func (ms *memstore) Release(ctx context.Context, key string, fingerprint string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	rec, ok := ms.records[key]
+	if ok && rec.Fingerprint == fingerprint && rec.Response == nil {
+		delete(ms.records, key)
+	}
+
+	return nil
+}
+
+func (ms *memstore) Purge(ctx context.Context) (int, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := time.Now()
+	purged := 0
+	for key, rec := range ms.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(ms.records, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func NewMemoryStore() store {
+	return &memstore{
+		records: make(map[string]*Record),
+	}
+}
@@ -0,0 +1,200 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/naughtygopher/errors"
+)
+
+// acquireAttempts is the number of times a key is tried to be acquired, when it's released by
+// another request in between the insert and reading it
+const acquireAttempts = 3
+
+type pgstore struct {
+	qbuilder  squirrel.StatementBuilderType
+	pqdriver  *pgxpool.Pool
+	tableName string
+}
+
+func (ps *pgstore) Acquire(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error) {
+	// an expired record is replaced, so the conflict is resolved only if the existing record has expired
+	query, args, err := ps.qbuilder.Insert(ps.tableName).Columns(
+		"idempotency_key",
+		"fingerprint",
+		"expires_at",
+	).Values(
+		key,
+		fingerprint,
+		squirrel.Expr("now() + make_interval(secs => ?)", lockTimeout.Seconds()),
+	).Suffix(fmt.Sprintf(
+		`ON CONFLICT (idempotency_key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			response_status = NULL,
+			response_headers = NULL,
+			response_body = NULL,
+			expires_at = EXCLUDED.expires_at,
+			created_at = now()
+		WHERE %s.expires_at <= now()
+		RETURNING idempotency_key`,
+		ps.tableName,
+	)).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	for range acquireAttempts {
+		acquired := ""
+		err = ps.pqdriver.QueryRow(ctx, query, args...).Scan(&acquired)
+		if err == nil {
+			return nil, nil
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.Wrap(err, "failed acquiring idempotency key")
+		}
+
+		rec, err := ps.getRecord(ctx, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the record was released or purged after the insert, so it can be tried again
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed getting idempotency key")
+		}
+
+		return rec, nil
+	}
+
+	return nil, errors.Errorf(
+		"failed acquiring idempotency key, it was released %d times while being acquired",
+		acquireAttempts,
+	)
+}
+
+func (ps *pgstore) getRecord(ctx context.Context, key string) (*Record, error) {
+	query, args, err := ps.qbuilder.Select(
+		"idempotency_key",
+		"fingerprint",
+		"response_status",
+		"response_headers",
+		"response_body",
+		"expires_at",
+	).From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"idempotency_key": key},
+	).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	var (
+		rec     = new(Record)
+		status  *int
+		headers []byte
+		body    []byte
+	)
+	err = ps.pqdriver.QueryRow(ctx, query, args...).Scan(
+		&rec.Key,
+		&rec.Fingerprint,
+		&status,
+		&headers,
+		&body,
+		&rec.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == nil {
+		return rec, nil
+	}
+
+	rec.Response = &Response{
+		Status: *status,
+		Header: make(http.Header),
+		Body:   body,
+	}
+	err = json.Unmarshal(headers, &rec.Response.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling response headers")
+	}
+
+	return rec, nil
+}
+
+func (ps *pgstore) Complete(ctx context.Context, key string, fingerprint string, resp *Response, ttl time.Duration) error {
+	headers, err := json.Marshal(resp.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling response headers")
+	}
+
+	query, args, err := ps.qbuilder.Update(ps.tableName).
+		Set("response_status", resp.Status).
+		Set("response_headers", headers).
+		Set("response_body", resp.Body).
+		Set("expires_at", squirrel.Expr("now() + make_interval(secs => ?)", ttl.Seconds())).
+		Where(squirrel.Eq{
+			"idempotency_key": key,
+			"fingerprint":     fingerprint,
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	_, err = ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed saving idempotent response")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) Release(ctx context.Context, key string, fingerprint string) error {
+	query, args, err := ps.qbuilder.Delete(ps.tableName).
+		Where(squirrel.Eq{
+			"idempotency_key": key,
+			"fingerprint":     fingerprint,
+			"response_status": nil,
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	_, err = ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed releasing idempotency key")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) Purge(ctx context.Context) (int, error) {
+	query, args, err := ps.qbuilder.Delete(ps.tableName).
+		Where(squirrel.Expr("expires_at <= now()")).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed purging idempotency keys")
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func NewPostgresStore(pqdriver *pgxpool.Pool, tableName string) store {
+	return &pgstore{
+		qbuilder:  squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		pqdriver:  pqdriver,
+		tableName: tableName,
+	}
+}
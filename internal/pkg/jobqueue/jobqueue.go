@@ -0,0 +1,405 @@
+// Package jobqueue is a durable background job queue. Jobs are persisted in a store (Postgres),
+// processed by a pool of workers, retried with exponential backoff on failure and dead-lettered
+// once all the attempts are exhausted.
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+)
+
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrNoHandler         = errors.New("no handler registered for the job kind")
+	ErrQueueNotAccepting = errors.New("job queue is not accepting jobs")
+	// ErrLeaseLost is returned when the outcome of an attempt is saved after its lease expired,
+	// and the job was claimed again, or completed, by another worker
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+type Status string
+
+const (
+	// StatusQueued is a job waiting to be processed, including the ones waiting to be retried
+	StatusQueued Status = "queued"
+	// StatusRunning is a job being processed by a worker
+	StatusRunning Status = "running"
+	// StatusSucceeded is a job which was processed successfully
+	StatusSucceeded Status = "succeeded"
+	// StatusDead is a job which failed in all its attempts, and will not be processed anymore
+	StatusDead Status = "dead"
+)
+
+type Job struct {
+	ID          string
+	Kind        string
+	Payload     json.RawMessage `json:",omitempty"`
+	Status      Status
+	Attempts    int
+	MaxAttempts int
+	// RunAt is the time after which the job can be picked up for processing
+	RunAt     time.Time
+	LastError string
+	Result    json.RawMessage `json:",omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Handler processes a job. The result returned, if not nil, is stored with the job as JSON.
+// A job is retried if the handler returns an error
+type Handler func(ctx context.Context, job *Job) (result any, err error)
+
+// TypedHandler returns a Handler which decodes the payload of the job into T, before calling fn
+func TypedHandler[T any](fn func(ctx context.Context, job *Job, payload T) (any, error)) Handler {
+	return func(ctx context.Context, job *Job) (any, error) {
+		var payload T
+		err := json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return nil, errors.InputBodyErrf(err, "invalid payload for job kind %s", job.Kind)
+		}
+		return fn(ctx, job, payload)
+	}
+}
+
+type store interface {
+	// SaveJob saves a new job
+	SaveJob(ctx context.Context, job *Job) error
+	GetJob(ctx context.Context, jobID string) (*Job, error)
+	// ClaimJob marks one job (of any of the kinds provided) which is due, as running and returns it.
+	// A running job whose lease has expired (e.g. the worker crashed) is also due. It returns nil
+	// if there are no jobs due
+	ClaimJob(ctx context.Context, kinds []string, lease time.Duration) (*Job, error)
+	// CompleteJob marks the job as succeeded, and saves the result. It returns ErrLeaseLost
+	// if the job is no longer running the attempt claimed
+	CompleteJob(ctx context.Context, job *Job, result json.RawMessage) error
+	// FailJob saves the failure. If retryAt is zero, the job is dead-lettered,
+	// otherwise it's queued again to be run at retryAt. It returns ErrLeaseLost
+	// if the job is no longer running the attempt claimed
+	FailJob(ctx context.Context, job *Job, reason string, retryAt time.Time) error
+}
+
+type Options struct {
+	// Workers is the number of jobs processed concurrently
+	Workers int
+	// PollInterval is the interval at which the store is checked for new jobs when idle
+	PollInterval time.Duration
+	// MaxAttempts is the number of times a job is attempted before it's dead-lettered
+	MaxAttempts int
+	// Lease is the maximum time a job can run, after which it's considered abandoned and picked
+	// up again by another worker
+	Lease time.Duration
+	// BackoffBase is the delay before the first retry, and doubles for every subsequent retry
+	BackoffBase time.Duration
+	// BackoffMax is the maximum delay between retries
+	BackoffMax time.Duration
+}
+
+func (opts *Options) setDefaults() {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = time.Minute * 5
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = time.Second
+	}
+	if opts.BackoffMax <= 0 {
+		opts.BackoffMax = time.Minute * 10
+	}
+}
+
+// Queue is the job queue along with its pool of workers
+type Queue struct {
+	store store
+	opts  Options
+
+	mutex    sync.RWMutex
+	handlers map[string]Handler
+	running  bool
+	stopping bool
+
+	// wakeup is used to notify idle workers when a job is enqueued within the same process
+	wakeup chan struct{}
+	// stop is closed when the workers should stop claiming new jobs
+	stop chan struct{}
+	// abort cancels the context of all the jobs in progress
+	abort   context.CancelFunc
+	workers sync.WaitGroup
+}
+
+// Register registers the handler for a job kind. Handlers should be registered before starting the queue
+func (q *Queue) Register(kind string, handler Handler) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.handlers[kind] = handler
+}
+
+// Enqueue adds a new job of the kind with payload, and returns the ID of the job
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any) (string, error) {
+	q.mutex.RLock()
+	_, ok := q.handlers[kind]
+	stopping := q.stopping
+	q.mutex.RUnlock()
+
+	if !ok {
+		return "", errors.InputBodyErr(ErrNoHandler, kind)
+	}
+
+	if stopping {
+		return "", errors.Wrap(ErrQueueNotAccepting)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", errors.InputBodyErr(err, "invalid job payload")
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.NewString(),
+		Kind:        kind,
+		Payload:     raw,
+		Status:      StatusQueued,
+		MaxAttempts: q.opts.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = q.store.SaveJob(ctx, job)
+	if err != nil {
+		return "", errors.Wrap(err, "failed saving job")
+	}
+
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+
+	return job.ID, nil
+}
+
+// Job returns the job with the ID
+func (q *Queue) Job(ctx context.Context, jobID string) (*Job, error) {
+	if jobID == "" {
+		return nil, errors.Validation("job ID is required")
+	}
+	return q.store.GetJob(ctx, jobID)
+}
+
+// Start starts the workers, which keep processing jobs until Shutdown is called
+func (q *Queue) Start(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.running {
+		return errors.New("job queue is already running")
+	}
+	q.running = true
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	// jobs in progress should not be affected by the parent context being cancelled. They're
+	// only aborted when the shutdown does not complete in time
+	jobsCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	q.abort = abort
+
+	for i := 0; i < q.opts.Workers; i++ {
+		q.workers.Add(1)
+		go q.work(jobsCtx, kinds)
+	}
+
+	logger.Info(ctx, fmt.Sprintf("[jobqueue] started %d workers for %v", q.opts.Workers, kinds))
+	return nil
+}
+
+// Shutdown stops the workers from claiming any new jobs, and waits for the jobs in progress to complete.
+// If the context is done before that, the jobs in progress are aborted. Aborted jobs are picked up
+// again after their lease expires
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mutex.Lock()
+	if !q.running || q.stopping {
+		q.mutex.Unlock()
+		return nil
+	}
+	q.stopping = true
+	close(q.stop)
+	q.mutex.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		q.abort()
+		return nil
+	case <-ctx.Done():
+		q.abort()
+		<-drained
+		return errors.Wrap(ctx.Err(), "job queue shutdown did not complete, jobs in progress were aborted")
+	}
+}
+
+func (q *Queue) work(ctx context.Context, kinds []string) {
+	defer q.workers.Done()
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		job, err := q.store.ClaimJob(ctx, kinds, q.opts.Lease)
+		if err != nil {
+			logger.Error(ctx, errors.Wrap(err, "[jobqueue] failed claiming job"))
+		}
+
+		if job != nil {
+			q.process(ctx, job)
+			continue
+		}
+
+		select {
+		case <-q.stop:
+			return
+		case <-q.wakeup:
+		case <-time.After(q.opts.PollInterval):
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job *Job) {
+	q.mutex.RLock()
+	handler := q.handlers[job.Kind]
+	q.mutex.RUnlock()
+
+	// a job can exceed the attempts when it's picked up again after its lease expired, e.g. worker crashed
+	if job.Attempts > job.MaxAttempts {
+		err := q.store.FailJob(ctx, job, "attempts exhausted, the last attempt did not complete", time.Time{})
+		if err != nil {
+			logSaveErr(ctx, errors.Wrapf(err, "[jobqueue] failed dead-lettering job %s", job.ID))
+		}
+		return
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, q.opts.Lease)
+	defer cancel()
+
+	result, err := q.run(jobCtx, handler, job)
+	if err == nil {
+		var raw json.RawMessage
+		if result != nil {
+			raw, err = json.Marshal(result)
+		}
+		if err == nil {
+			err = q.store.CompleteJob(ctx, job, raw)
+			if err != nil {
+				logSaveErr(ctx, errors.Wrapf(err, "[jobqueue] failed completing job %s", job.ID))
+			}
+			return
+		}
+	}
+
+	retryAt := time.Time{}
+	if job.Attempts < job.MaxAttempts {
+		retryAt = time.Now().Add(Backoff(q.opts.BackoffBase, q.opts.BackoffMax, job.Attempts))
+	}
+
+	// only the job ID & kind are logged, since payloads can have sensitive data
+	if retryAt.IsZero() {
+		logger.Error(ctx, fmt.Sprintf(
+			"[jobqueue] job %s (%s) dead-lettered after %d attempts", job.ID, job.Kind, job.Attempts,
+		), err)
+	} else {
+		logger.Warn(ctx, fmt.Sprintf(
+			"[jobqueue] job %s (%s) failed attempt %d, retrying at %s",
+			job.ID, job.Kind, job.Attempts, retryAt.Format(time.RFC3339),
+		), err)
+	}
+
+	// the error saved with the job can be exposed to users, so it shouldn't have internal details
+	// like file & line numbers
+	reason, _ := errors.Message(err)
+	if reason == "" {
+		reason = err.Error()
+	}
+
+	ferr := q.store.FailJob(ctx, job, reason, retryAt)
+	if ferr != nil {
+		logSaveErr(ctx, errors.Wrapf(ferr, "[jobqueue] failed saving failure of job %s", job.ID))
+	}
+}
+
+// logSaveErr logs the error of saving the outcome of an attempt. A lost lease is only a warning,
+// since the job is taken over by another worker and the outcome of this attempt is discarded
+func logSaveErr(ctx context.Context, err error) {
+	if errors.Is(err, ErrLeaseLost) {
+		logger.Warn(ctx, err.Error())
+		return
+	}
+	logger.Error(ctx, err)
+}
+
+func (q *Queue) run(ctx context.Context, handler Handler, job *Job) (result any, err error) {
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = errors.Errorf("panic: %+v", rec)
+		}
+	}()
+
+	if handler == nil {
+		return nil, errors.InputBodyErr(ErrNoHandler, job.Kind)
+	}
+
+	return handler(ctx, job)
+}
+
+// Backoff returns the delay before the next attempt, after the given number of attempts.
+// It is base * 2^(attempts-1), limited to maxDelay
+func Backoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxDelay || delay <= 0 {
+			return maxDelay
+		}
+	}
+
+	return min(delay, maxDelay)
+}
+
+func New(st store, opts Options) *Queue {
+	opts.setDefaults()
+	return &Queue{
+		store:    st,
+		opts:     opts,
+		handlers: make(map[string]Handler),
+		wakeup:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
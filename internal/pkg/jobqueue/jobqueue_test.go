@@ -0,0 +1,204 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestQueue() *Queue {
+	return New(NewMemoryStore(), Options{
+		Workers:      2,
+		PollInterval: time.Millisecond * 5,
+		MaxAttempts:  3,
+		BackoffBase:  time.Millisecond,
+		BackoffMax:   time.Millisecond * 5,
+	})
+}
+
+func waitForStatus(t *testing.T, q *Queue, jobID string, status Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 2)
+	for time.Now().Before(deadline) {
+		job, err := q.Job(context.Background(), jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status == status {
+			return job
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+	t.Fatalf("job %s did not reach status %s", jobID, status)
+	return nil
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue()
+
+	failures := atomic.Int32{}
+	q.Register("sum", TypedHandler(func(ctx context.Context, job *Job, numbers []int) (any, error) {
+		// fails the first attempt, to be retried
+		if failures.Add(1) == 1 {
+			return nil, errors.New("temporary failure")
+		}
+		sum := 0
+		for _, n := range numbers {
+			sum += n
+		}
+		return sum, nil
+	}))
+	q.Register("fail", func(ctx context.Context, job *Job) (any, error) {
+		panic("always fails")
+	})
+
+	_, err := q.Enqueue(ctx, "unknown", nil)
+	if err == nil {
+		t.Error("expected error enqueuing a job kind without handler, got nil")
+	}
+
+	err = q.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = q.Shutdown(ctx) }()
+
+	sumID, err := q.Enqueue(ctx, "sum", []int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failID, err := q.Enqueue(ctx, "fail", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForStatus(t, q, sumID, StatusSucceeded)
+	if job.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", job.Attempts)
+	}
+	sum := 0
+	_ = json.Unmarshal(job.Result, &sum)
+	if sum != 6 {
+		t.Errorf("expected result 6, got %s", string(job.Result))
+	}
+
+	job = waitForStatus(t, q, failID, StatusDead)
+	if job.Attempts != 3 {
+		t.Errorf("expected 3 attempts before dead-lettering, got %d", job.Attempts)
+	}
+	if job.LastError == "" {
+		t.Error("expected last error to be saved")
+	}
+}
+
+func TestQueue_ShutdownDrains(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue()
+
+	started := make(chan struct{})
+	q.Register("slow", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		time.Sleep(time.Millisecond * 50)
+		return nil, nil
+	})
+
+	err := q.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobID, err := q.Enqueue(ctx, "slow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	err = q.Shutdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := q.Job(ctx, jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusSucceeded {
+		t.Errorf("expected the job in progress to complete before shutdown, got status %s", job.Status)
+	}
+
+	_, err = q.Enqueue(ctx, "slow", nil)
+	if !errors.Is(err, ErrQueueNotAccepting) {
+		t.Errorf("expected ErrQueueNotAccepting after shutdown, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, maxDelay := time.Second, time.Minute
+	for attempts, expected := range map[int]time.Duration{
+		0:  time.Second,
+		1:  time.Second,
+		2:  time.Second * 2,
+		4:  time.Second * 8,
+		7:  time.Minute,
+		70: time.Minute,
+	} {
+		if got := Backoff(base, maxDelay, attempts); got != expected {
+			t.Errorf("attempts %d: expected %s, got %s", attempts, expected, got)
+		}
+	}
+}
+
+func TestMemoryStoreLeaseLost(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStore()
+
+	now := time.Now()
+	err := st.SaveJob(ctx, &Job{
+		ID:          "job-1",
+		Kind:        "sum",
+		Status:      StatusQueued,
+		MaxAttempts: 3,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the lease expires immediately, so the job is claimed again by another worker
+	first, err := st.ClaimJob(ctx, []string{"sum"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	second, err := st.ClaimJob(ctx, []string{"sum"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == nil || second.Attempts != 2 {
+		t.Fatalf("expected the job to be claimed again with attempt 2, got %+v", second)
+	}
+
+	err = st.CompleteJob(ctx, first, nil)
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost completing the expired attempt, got %v", err)
+	}
+	err = st.FailJob(ctx, first, "failed", time.Time{})
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost failing the expired attempt, got %v", err)
+	}
+
+	err = st.CompleteJob(ctx, second, json.RawMessage(`1`))
+	if err != nil {
+		t.Fatalf("expected the current attempt to complete, got %v", err)
+	}
+	err = st.FailJob(ctx, second, "failed", time.Time{})
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost failing a completed job, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+// memstore is an in-memory store, meant for local development & tests. Jobs are lost on exit
+type memstore struct {
+	mutex sync.Mutex
+	jobs  map[string]*Job
+}
+
+func copyJob(job *Job) *Job {
+	cjob := *job
+	cjob.Payload = slices.Clone(job.Payload)
+	cjob.Result = slices.Clone(job.Result)
+	return &cjob
+}
+
+func (ms *memstore) SaveJob(ctx context.Context, job *Job) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, ok := ms.jobs[job.ID]; ok {
+		return errors.Duplicatef("job %s already exists", job.ID)
+	}
+	ms.jobs[job.ID] = copyJob(job)
+
+	return nil
+}
+
+func (ms *memstore) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	job, ok := ms.jobs[jobID]
+	if !ok {
+		return nil, errors.NotFoundErr(ErrJobNotFound, jobID)
+	}
+
+	return copyJob(job), nil
+}
+
+func (ms *memstore) ClaimJob(ctx context.Context, kinds []string, lease time.Duration) (*Job, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := time.Now()
+	var due *Job
+	for _, job := range ms.jobs {
+		if !slices.Contains(kinds, job.Kind) || job.RunAt.After(now) {
+			continue
+		}
+		if job.Status != StatusQueued && job.Status != StatusRunning {
+			continue
+		}
+		if job.Status == StatusRunning && !job.RunAt.Before(now) {
+			continue
+		}
+		if due == nil || job.RunAt.Before(due.RunAt) ||
+			(job.RunAt.Equal(due.RunAt) && job.CreatedAt.Before(due.CreatedAt)) {
+			due = job
+		}
+	}
+
+	if due == nil {
+		return nil, nil
+	}
+
+	due.Status = StatusRunning
+	due.Attempts++
+	// for a running job, run_at is the time until which the job is leased to the worker
+	due.RunAt = now.Add(lease)
+	due.UpdatedAt = now
+
+	return copyJob(due), nil
+}
+
+func (ms *memstore) CompleteJob(ctx context.Context, job *Job, result json.RawMessage) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	sjob, err := ms.claimedJob(job)
+	if err != nil {
+		return err
+	}
+
+	sjob.Status = StatusSucceeded
+	sjob.Result = slices.Clone(result)
+	sjob.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (ms *memstore) FailJob(ctx context.Context, job *Job, reason string, retryAt time.Time) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	sjob, err := ms.claimedJob(job)
+	if err != nil {
+		return err
+	}
+
+	sjob.LastError = reason
+	sjob.UpdatedAt = time.Now()
+	if retryAt.IsZero() {
+		sjob.Status = StatusDead
+	} else {
+		sjob.Status = StatusQueued
+		sjob.RunAt = retryAt
+	}
+
+	return nil
+}
+
+// claimedJob returns the stored job, if it's still running the attempt claimed. The caller
+// should hold the lock
+func (ms *memstore) claimedJob(job *Job) (*Job, error) {
+	sjob, ok := ms.jobs[job.ID]
+	if !ok {
+		return nil, errors.NotFoundErr(ErrJobNotFound, job.ID)
+	}
+
+	if sjob.Status != StatusRunning || sjob.Attempts != job.Attempts {
+		return nil, errors.Wrapf(ErrLeaseLost, "job %s, attempt %d", job.ID, job.Attempts)
+	}
+
+	return sjob, nil
+}
+
+func NewMemoryStore() store {
+	return &memstore{
+		jobs: make(map[string]*Job),
+	}
+}
@@ -0,0 +1,232 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/naughtygopher/errors"
+)
+
+var jobColumns = []string{
+	"id",
+	"kind",
+	"payload",
+	"status",
+	"attempts",
+	"max_attempts",
+	"run_at",
+	"last_error",
+	"result",
+	"created_at",
+	"updated_at",
+}
+
+type pgstore struct {
+	qbuilder  squirrel.StatementBuilderType
+	pqdriver  *pgxpool.Pool
+	tableName string
+}
+
+func scanJob(row pgx.Row) (*Job, error) {
+	job := &Job{}
+	var lastError *string
+	err := row.Scan(
+		&job.ID,
+		&job.Kind,
+		&job.Payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&lastError,
+		&job.Result,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError != nil {
+		job.LastError = *lastError
+	}
+
+	return job, nil
+}
+
+func (ps *pgstore) SaveJob(ctx context.Context, job *Job) error {
+	query, args, err := ps.qbuilder.Insert(ps.tableName).Columns(
+		"id",
+		"kind",
+		"payload",
+		"status",
+		"max_attempts",
+		"run_at",
+		"created_at",
+		"updated_at",
+	).Values(
+		job.ID,
+		job.Kind,
+		job.Payload,
+		string(job.Status),
+		job.MaxAttempts,
+		job.RunAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	_, err = ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed storing job")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	if uuid.Validate(jobID) != nil {
+		return nil, errors.NotFoundErr(ErrJobNotFound, jobID)
+	}
+
+	query, args, err := ps.qbuilder.Select(jobColumns...).From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"id": jobID},
+	).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	job, err := scanJob(ps.pqdriver.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.NotFoundErr(ErrJobNotFound, jobID)
+		}
+		return nil, errors.Wrap(err, "failed getting job")
+	}
+
+	return job, nil
+}
+
+func (ps *pgstore) ClaimJob(ctx context.Context, kinds []string, lease time.Duration) (*Job, error) {
+	// SKIP LOCKED lets multiple workers (across replicas) claim different jobs concurrently,
+	// without waiting on each other
+	// the subquery should use the default placeholder format, since the placeholders are
+	// replaced only once by the outer query
+	dueJob := squirrel.Select("id").From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"kind": kinds},
+	).Where(
+		squirrel.Or{
+			squirrel.And{
+				squirrel.Eq{"status": string(StatusQueued)},
+				squirrel.Expr("run_at <= now()"),
+			},
+			squirrel.And{
+				squirrel.Eq{"status": string(StatusRunning)},
+				squirrel.Expr("run_at < now()"),
+			},
+		},
+	).OrderBy(
+		"run_at", "created_at",
+	).Limit(1).Suffix("FOR UPDATE SKIP LOCKED")
+
+	// for a running job, run_at is the time until which the job is leased to the worker
+	query, args, err := ps.qbuilder.Update(ps.tableName).
+		Set("status", string(StatusRunning)).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("run_at", squirrel.Expr("now() + make_interval(secs => ?)", lease.Seconds())).
+		Where(squirrel.Expr("id = (?)", dueJob)).
+		Suffix("RETURNING " + strings.Join(jobColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	job, err := scanJob(ps.pqdriver.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed claiming job")
+	}
+
+	return job, nil
+}
+
+// claimedJob is the condition to update a job only if it's still running the attempt claimed,
+// i.e. the lease did not expire and the job was not claimed again by another worker
+func claimedJob(job *Job) squirrel.Eq {
+	return squirrel.Eq{
+		"id":       job.ID,
+		"status":   string(StatusRunning),
+		"attempts": job.Attempts,
+	}
+}
+
+func (ps *pgstore) CompleteJob(ctx context.Context, job *Job, result json.RawMessage) error {
+	query, args, err := ps.qbuilder.Update(ps.tableName).
+		Set("status", string(StatusSucceeded)).
+		Set("result", result).
+		Where(claimedJob(job)).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed completing job")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.Wrapf(ErrLeaseLost, "job %s, attempt %d", job.ID, job.Attempts)
+	}
+
+	return nil
+}
+
+func (ps *pgstore) FailJob(ctx context.Context, job *Job, reason string, retryAt time.Time) error {
+	qbuilder := ps.qbuilder.Update(ps.tableName).
+		Set("last_error", reason).
+		Where(claimedJob(job))
+	if retryAt.IsZero() {
+		qbuilder = qbuilder.Set("status", string(StatusDead))
+	} else {
+		qbuilder = qbuilder.Set("status", string(StatusQueued)).Set("run_at", retryAt)
+	}
+
+	query, args, err := qbuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed saving job failure")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.Wrapf(ErrLeaseLost, "job %s, attempt %d", job.ID, job.Attempts)
+	}
+
+	return nil
+}
+
+func NewPostgresStore(pqdriver *pgxpool.Pool, tableName string) store {
+	return &pgstore{
+		qbuilder:  squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		pqdriver:  pqdriver,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,233 @@
+// Package lifecycle manages the startup & shutdown of the components of the app (servers,
+// database connections, APM etc.). Components are started in the order of their dependencies,
+// and stopped in the reverse order.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+// ErrStopSkipped is the error of a component which was not stopped, because a component depending
+// on it did not stop in time and could still be using it
+var ErrStopSkipped = errors.New("stop skipped")
+
+// Component is any part of the app which needs to be started and/or stopped
+type Component struct {
+	Name string
+	// DependsOn has the names of the components which should be started before, and
+	// stopped after this component
+	DependsOn []string
+	// Start should return only after the component is ready to be used, e.g. a server
+	// should start listening in a separate goroutine
+	Start func(ctx context.Context) error
+	// Stop should stop the component gracefully, within the context deadline
+	Stop func(ctx context.Context) error
+	// StopTimeout if > 0, is the maximum time allowed for stopping this component.
+	// Otherwise the component can use the whole of the remaining time allowed for shutdown
+	StopTimeout time.Duration
+}
+
+// Result is the result of stopping a component
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Manager starts & stops the registered components
+type Manager struct {
+	mutex      sync.Mutex
+	components []*Component
+	byName     map[string]*Component
+	// started has the components which were started successfully, in the order of start
+	started []*Component
+}
+
+// Register adds a component to be managed. The component names should be unique
+func (mgr *Manager) Register(comp *Component) error {
+	mgr.mutex.Lock()
+	defer mgr.mutex.Unlock()
+
+	if comp == nil || comp.Name == "" {
+		return errors.Validation("component name cannot be empty")
+	}
+
+	if _, ok := mgr.byName[comp.Name]; ok {
+		return errors.Duplicatef("component '%s' is already registered", comp.Name)
+	}
+
+	mgr.components = append(mgr.components, comp)
+	mgr.byName[comp.Name] = comp
+	return nil
+}
+
+// order returns the components sorted topologically based on the dependencies. Among the components
+// whose dependencies are met at the same time, the order of registration is maintained
+func (mgr *Manager) order() ([]*Component, error) {
+	pending := make(map[string]int, len(mgr.components))
+	dependents := make(map[string][]*Component, len(mgr.components))
+	for _, comp := range mgr.components {
+		for _, dep := range comp.DependsOn {
+			if _, ok := mgr.byName[dep]; !ok {
+				return nil, errors.Validationf("component '%s' depends on '%s', which is not registered", comp.Name, dep)
+			}
+			dependents[dep] = append(dependents[dep], comp)
+		}
+		pending[comp.Name] = len(comp.DependsOn)
+	}
+
+	ordered := make([]*Component, 0, len(mgr.components))
+	for _, comp := range mgr.components {
+		if pending[comp.Name] == 0 {
+			ordered = append(ordered, comp)
+		}
+	}
+
+	for idx := 0; idx < len(ordered); idx++ {
+		for _, dependent := range dependents[ordered[idx].Name] {
+			pending[dependent.Name]--
+			if pending[dependent.Name] == 0 {
+				ordered = append(ordered, dependent)
+			}
+		}
+	}
+
+	if len(ordered) != len(mgr.components) {
+		cyclic := make([]string, 0)
+		for _, comp := range mgr.components {
+			if pending[comp.Name] > 0 {
+				cyclic = append(cyclic, comp.Name)
+			}
+		}
+		return nil, errors.Validationf("cyclic dependency among components %v", cyclic)
+	}
+
+	return ordered, nil
+}
+
+// Start starts all the components in the order of their dependencies. It stops at the first failure,
+// and the components started until then are stopped by calling Stop
+func (mgr *Manager) Start(ctx context.Context) error {
+	mgr.mutex.Lock()
+	defer mgr.mutex.Unlock()
+
+	ordered, err := mgr.order()
+	if err != nil {
+		return err
+	}
+
+	for _, comp := range ordered {
+		if comp.Start != nil {
+			err = comp.Start(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "failed starting '%s'", comp.Name)
+			}
+		}
+		mgr.started = append(mgr.started, comp)
+	}
+
+	return nil
+}
+
+// Stop stops all the started components, in the reverse order of start. A component is stopped
+// only after all its dependents are stopped. Failure to stop a component does not prevent
+// the rest from being stopped. Though if a component does not stop within its timeout, it is
+// abandoned while still running. So the components it depends on (directly or indirectly) are
+// not stopped, and are reported with ErrStopSkipped
+func (mgr *Manager) Stop(ctx context.Context) []Result {
+	mgr.mutex.Lock()
+	defer mgr.mutex.Unlock()
+
+	// running has the components which may still be running after Stop was called
+	running := make(map[string]bool)
+	results := make([]Result, 0, len(mgr.started))
+	for idx := len(mgr.started) - 1; idx >= 0; idx-- {
+		comp := mgr.started[idx]
+		dependent := mgr.runningDependent(comp, running)
+		if dependent != "" {
+			running[comp.Name] = true
+			results = append(results, Result{
+				Name: comp.Name,
+				Err:  errors.Wrapf(ErrStopSkipped, "'%s' depending on '%s' is still running", dependent, comp.Name),
+			})
+			continue
+		}
+
+		if comp.Stop == nil {
+			continue
+		}
+
+		result, abandoned := stop(ctx, comp)
+		if abandoned {
+			running[comp.Name] = true
+		}
+		results = append(results, result)
+	}
+	mgr.started = nil
+
+	return results
+}
+
+// runningDependent returns the name of a started component which depends on comp, and is
+// still running. An empty string is returned if there's none
+func (mgr *Manager) runningDependent(comp *Component, running map[string]bool) string {
+	for _, started := range mgr.started {
+		if !running[started.Name] {
+			continue
+		}
+		for _, dep := range started.DependsOn {
+			if dep == comp.Name {
+				return started.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// stop stops the component, and returns true if it was abandoned for not stopping in time
+func stop(ctx context.Context, comp *Component) (Result, bool) {
+	if comp.StopTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, comp.StopTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			rec := recover()
+			if rec != nil {
+				done <- errors.Errorf("panic while stopping: %+v", rec)
+			}
+		}()
+		done <- comp.Stop(ctx)
+	}()
+
+	var err error
+	abandoned := false
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// the component did not respect the context, so it's abandoned
+		err = errors.Wrapf(ctx.Err(), "timed out stopping '%s'", comp.Name)
+		abandoned = true
+	}
+
+	return Result{
+		Name:     comp.Name,
+		Err:      err,
+		Duration: time.Since(start),
+	}, abandoned
+}
+
+func New() *Manager {
+	return &Manager{
+		byName: make(map[string]*Component),
+	}
+}
@@ -0,0 +1,138 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	events := make([]string, 0)
+	component := func(name string, deps ...string) *Component {
+		return &Component{
+			Name:      name,
+			DependsOn: deps,
+			Start: func(ctx context.Context) error {
+				events = append(events, "start "+name)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				events = append(events, "stop "+name)
+				return nil
+			},
+		}
+	}
+
+	mgr := New()
+	for _, comp := range []*Component{
+		component("http", "api", "apm"),
+		component("api", "postgres"),
+		component("apm"),
+		component("postgres"),
+	} {
+		err := mgr.Register(comp)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := mgr.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := mgr.Stop(context.Background())
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("unexpected error stopping '%s': %v", res.Name, res.Err)
+		}
+	}
+
+	expected := []string{
+		"start apm", "start postgres", "start api", "start http",
+		"stop http", "stop api", "stop postgres", "stop apm",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+}
+
+func TestManager_InvalidDependencies(t *testing.T) {
+	mgr := New()
+	_ = mgr.Register(&Component{Name: "a", DependsOn: []string{"b"}})
+	_ = mgr.Register(&Component{Name: "b", DependsOn: []string{"a"}})
+	if err := mgr.Start(context.Background()); err == nil {
+		t.Error("expected error for cyclic dependency, got nil")
+	}
+
+	mgr = New()
+	_ = mgr.Register(&Component{Name: "a", DependsOn: []string{"missing"}})
+	if err := mgr.Start(context.Background()); err == nil {
+		t.Error("expected error for missing dependency, got nil")
+	}
+
+	if err := mgr.Register(&Component{Name: "a"}); err == nil {
+		t.Error("expected error for duplicate component, got nil")
+	}
+}
+
+func TestManager_StopFailures(t *testing.T) {
+	stopped := map[string]bool{}
+	mgr := New()
+	_ = mgr.Register(&Component{
+		Name: "independent",
+		Stop: func(ctx context.Context) error {
+			stopped["independent"] = true
+			return nil
+		},
+	})
+	_ = mgr.Register(&Component{
+		Name: "first",
+		Stop: func(ctx context.Context) error {
+			stopped["first"] = true
+			return nil
+		},
+	})
+	_ = mgr.Register(&Component{
+		Name:        "slow",
+		DependsOn:   []string{"first"},
+		StopTimeout: time.Millisecond * 10,
+		Stop: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+	_ = mgr.Register(&Component{
+		Name:      "failing",
+		DependsOn: []string{"slow"},
+		Start: func(ctx context.Context) error {
+			return errors.New("failed")
+		},
+		Stop: func(ctx context.Context) error {
+			t.Error("component which failed to start should not be stopped")
+			return nil
+		},
+	})
+
+	err := mgr.Start(context.Background())
+	if err == nil {
+		t.Fatal("expected start error, got nil")
+	}
+
+	results := mgr.Stop(context.Background())
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Name != "slow" || !errors.Is(results[0].Err, context.DeadlineExceeded) {
+		t.Errorf("expected 'slow' to time out, got %+v", results[0])
+	}
+	// 'slow' could still be using 'first', so it should not be stopped
+	if results[1].Name != "first" || !errors.Is(results[1].Err, ErrStopSkipped) || stopped["first"] {
+		t.Errorf("expected stopping 'first' to be skipped, got %+v", results[1])
+	}
+	if results[2].Name != "independent" || results[2].Err != nil || !stopped["independent"] {
+		t.Errorf("expected 'independent' to be stopped, got %+v", results[2])
+	}
+}
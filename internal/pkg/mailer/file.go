@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/naughtygopher/errors"
+)
+
+// messageSeparator separates the messages appended to the file
+const messageSeparator = "\r\n----------\r\n\r\n"
+
+// File appends all the emails to a file instead of sending them, so that they can be read
+// during local development
+type File struct {
+	mutex sync.Mutex
+	path  string
+	from  string
+}
+
+func (fm *File) Send(ctx context.Context, msg *Message) error {
+	err := msg.Validate()
+	if err != nil {
+		return err
+	}
+
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	file, err := os.OpenFile(fm.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed opening %s", fm.path)
+	}
+
+	_, err = file.Write(append(format(fm.from, msg), messageSeparator...))
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrapf(err, "failed writing to %s", fm.path)
+	}
+
+	return file.Close()
+}
+
+func NewFile(path string, from string) *File {
+	return &File{
+		path: path,
+		from: from,
+	}
+}
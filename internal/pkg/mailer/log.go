@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+)
+
+// Log logs all the emails instead of sending them, it's the default mailer for local development
+type Log struct {
+	from string
+}
+
+func (lm *Log) Send(ctx context.Context, msg *Message) error {
+	err := msg.Validate()
+	if err != nil {
+		return err
+	}
+
+	logger.Info(ctx, fmt.Sprintf(
+		"[mailer] from: %s, to: %s, subject: %s\n%s",
+		lm.from, msg.To, msg.Subject, msg.Body,
+	))
+
+	return nil
+}
+
+func NewLog(from string) *Log {
+	return &Log{from: from}
+}
@@ -0,0 +1,71 @@
+// Package mailer sends emails. The SMTP mailer is meant for staging & production, while the file
+// and log mailers are meant for local development & tests, where a mail server is not available
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Validate returns an error if the recipient is not a valid address, or if the subject has
+// line breaks, which would allow injecting headers
+func (msg *Message) Validate() error {
+	if msg == nil {
+		return errors.Validation("empty message")
+	}
+
+	_, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return errors.ValidationErrf(err, "invalid recipient %q", msg.To)
+	}
+
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.Validation("subject cannot have line breaks")
+	}
+
+	return nil
+}
+
+// Mailer is implemented by all the mailers
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// bareAddress returns only the email address of addr, without the display name if any
+func bareAddress(addr string) (string, error) {
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil {
+		return "", errors.ValidationErrf(err, "invalid address %q", addr)
+	}
+	return parsed.Address, nil
+}
+
+// format returns the message in the Internet Message Format (RFC 5322), from the sender 'from'
+func format(from string, msg *Message) []byte {
+	buff := bytes.NewBuffer(nil)
+	fmt.Fprintf(buff, "From: %s\r\n", from)
+	fmt.Fprintf(buff, "To: %s\r\n", msg.To)
+	fmt.Fprintf(buff, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buff, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buff.WriteString("MIME-Version: 1.0\r\n")
+	buff.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buff.WriteString("\r\n")
+	buff.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buff.WriteString("\r\n")
+
+	return buff.Bytes()
+}
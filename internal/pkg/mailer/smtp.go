@@ -0,0 +1,118 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/naughtygopher/errors"
+)
+
+// SMTPConfig has the configurations of the SMTP server
+type SMTPConfig struct {
+	Host string
+	Port uint16
+	// Username & Password are optional, authentication is done only if Username is set
+	Username string
+	Password string
+	// From is the address of the sender, e.g. "goapp <noreply@example.com>"
+	From string
+}
+
+// SMTP sends emails via an SMTP server. STARTTLS is used if the server supports it, and
+// is required for authentication
+type SMTP struct {
+	cfg SMTPConfig
+}
+
+func (sm *SMTP) Send(ctx context.Context, msg *Message) error {
+	err := msg.Validate()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(sm.cfg.Host, strconv.Itoa(int(sm.cfg.Port)))
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed connecting to SMTP server %s", addr)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sm.cfg.Host)
+	if err != nil {
+		return errors.Wrap(err, "failed starting SMTP session")
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: sm.cfg.Host})
+		if err != nil {
+			return errors.Wrap(err, "failed starting TLS")
+		}
+	}
+
+	if sm.cfg.Username != "" {
+		// PlainAuth refuses to send the credentials over an unencrypted connection, except to localhost
+		err = client.Auth(smtp.PlainAuth("", sm.cfg.Username, sm.cfg.Password, sm.cfg.Host))
+		if err != nil {
+			return errors.Wrap(err, "failed authenticating with SMTP server")
+		}
+	}
+
+	err = sm.send(client, msg)
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (sm *SMTP) send(client *smtp.Client, msg *Message) error {
+	from, err := bareAddress(sm.cfg.From)
+	if err != nil {
+		return err
+	}
+
+	to, err := bareAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return errors.Wrap(err, "failed setting sender")
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return errors.Wrap(err, "failed setting recipient")
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed starting message")
+	}
+
+	_, err = wc.Write(format(sm.cfg.From, msg))
+	if err != nil {
+		_ = wc.Close()
+		return errors.Wrap(err, "failed writing message")
+	}
+
+	err = wc.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed sending message")
+	}
+
+	return nil
+}
+
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	return &SMTP{cfg: cfg}
+}
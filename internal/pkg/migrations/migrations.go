@@ -0,0 +1,351 @@
+// Package migrations applies versioned SQL migrations to a Postgres database. Every migration is
+// a pair of files named '<version>_<name>.up.sql' & '<version>_<name>.down.sql', and the applied
+// versions are recorded in a table. A Postgres advisory lock is held while migrating, so that
+// multiple replicas of the application starting at the same time do not race each other.
+package migrations
+
+import (
+	"context"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/naughtygopher/errors"
+)
+
+const DefaultTableName = "schema_migrations"
+
+var filenameRegex = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a single version of the schema
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is the state of a migration in the database
+type Status struct {
+	Version uint64
+	Name    string
+	// AppliedAt is zero if the migration is pending
+	AppliedAt time.Time
+	// Unknown is true if the version is applied in the database, but there's no migration file for it
+	Unknown bool
+}
+
+// Migrator applies & rolls back migrations
+type Migrator struct {
+	pool       *pgxpool.Pool
+	tableName  string
+	lockID     int64
+	migrations []Migration
+}
+
+// Up applies all the pending migrations in order of their version, and returns the applied ones
+func (mig *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0, len(mig.migrations))
+	err := mig.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := mig.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range mig.migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+
+			err = mig.apply(ctx, conn, m, true)
+			if err != nil {
+				return err
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return applied, err
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the latest 'steps' applied migrations, and returns the rolled back ones
+func (mig *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.Validation("steps should be greater than 0")
+	}
+
+	rolledback := make([]Migration, 0, steps)
+	err := mig.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := mig.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(mig.migrations) - 1; i >= 0 && len(rolledback) < steps; i-- {
+			m := mig.migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+
+			err = mig.apply(ctx, conn, m, false)
+			if err != nil {
+				return err
+			}
+			rolledback = append(rolledback, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return rolledback, err
+	}
+
+	return rolledback, nil
+}
+
+// Status returns the status of all the known migrations, as well as the applied versions which
+// do not have a migration
+func (mig *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := mig.pool.Acquire(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed acquiring connection")
+	}
+	defer conn.Release()
+
+	err = mig.createTable(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := mig.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Status, 0, len(mig.migrations))
+	for _, m := range mig.migrations {
+		list = append(list, Status{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: done[m.Version],
+		})
+		delete(done, m.Version)
+	}
+
+	for version, appliedAt := range done {
+		list = append(list, Status{
+			Version:   version,
+			AppliedAt: appliedAt,
+			Unknown:   true,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+
+	return list, nil
+}
+
+// withLock runs fn while holding the advisory lock. Advisory locks are bound to the session,
+// hence the same connection is used for acquiring the lock, migrating & releasing the lock
+func (mig *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := mig.pool.Acquire(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed acquiring connection")
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", mig.lockID)
+	if err != nil {
+		return errors.Wrap(err, "failed acquiring migration lock")
+	}
+	defer func() {
+		// the context could be cancelled by now, the lock should be released irrespective
+		_, _ = conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", mig.lockID)
+	}()
+
+	err = mig.createTable(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (mig *Migrator) createTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS `+pgx.Identifier{mig.tableName}.Sanitize()+` (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)`,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed creating migrations table")
+	}
+
+	return nil
+}
+
+func (mig *Migrator) appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[uint64]time.Time, error) {
+	rows, err := conn.Query(
+		ctx,
+		"SELECT version, applied_at FROM "+pgx.Identifier{mig.tableName}.Sanitize(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading applied migrations")
+	}
+	defer rows.Close()
+
+	done := map[uint64]time.Time{}
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading applied migration")
+		}
+		done[uint64(version)] = appliedAt
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading applied migrations")
+	}
+
+	return done, nil
+}
+
+// apply runs the up or down migration, along with recording it, in a single transaction
+func (mig *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, m Migration, up bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed starting transaction")
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	table := pgx.Identifier{mig.tableName}.Sanitize()
+	if up {
+		_, err = tx.Exec(ctx, m.Up)
+		if err == nil {
+			_, err = tx.Exec(ctx, "INSERT INTO "+table+" (version, name) VALUES ($1, $2)", int64(m.Version), m.Name)
+		}
+	} else {
+		_, err = tx.Exec(ctx, m.Down)
+		if err == nil {
+			_, err = tx.Exec(ctx, "DELETE FROM "+table+" WHERE version = $1", int64(m.Version))
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed migrating %d_%s (up: %t)", m.Version, m.Name, up)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed committing %d_%s (up: %t)", m.Version, m.Name, up)
+	}
+
+	return nil
+}
+
+// Load reads all the migrations in the root of fsys, sorted by version. Every version must
+// have both up & down migrations
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading migrations")
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		parts := filenameRegex.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, errors.Validationf(
+				"invalid migration filename '%s', expected <version>_<name>.<up|down>.sql",
+				entry.Name(),
+			)
+		}
+
+		version, err := strconv.ParseUint(parts[1], 10, 63)
+		if err != nil {
+			return nil, errors.ValidationErrf(err, "invalid migration version '%s'", parts[1])
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		} else if m.Name != parts[2] {
+			return nil, errors.Validationf("multiple migrations with version %d", version)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading migration '%s'", entry.Name())
+		}
+
+		if parts[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, errors.Validationf("migration %d_%s should have both up & down", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+
+	return list, nil
+}
+
+// New returns a Migrator for all the migrations in the root of fsys. If tableName is empty,
+// DefaultTableName is used for recording the applied versions
+func New(pool *pgxpool.Pool, fsys fs.FS, tableName string) (*Migrator, error) {
+	if tableName == "" {
+		tableName = DefaultTableName
+	}
+
+	list, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	// lock ID is derived from the table name, so that independent sets of migrations
+	// in the same database do not block each other
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(tableName))
+
+	return &Migrator{
+		pool:       pool,
+		tableName:  tableName,
+		lockID:     int64(hasher.Sum64()),
+		migrations: list,
+	}, nil
+}
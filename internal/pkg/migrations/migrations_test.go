@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"0010_notes.up.sql":   {Data: []byte("up 10")},
+				"0010_notes.down.sql": {Data: []byte("down 10")},
+				"0002_users.up.sql":   {Data: []byte("up 2")},
+				"0002_users.down.sql": {Data: []byte("down 2")},
+			},
+			want: []Migration{
+				{Version: 2, Name: "users", Up: "up 2", Down: "down 2"},
+				{Version: 10, Name: "notes", Up: "up 10", Down: "down 10"},
+			},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"0001_users.up.sql": {Data: []byte("up")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid filename",
+			fsys: fstest.MapFS{
+				"users.sql": {Data: []byte("up")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"0001_users.up.sql":   {Data: []byte("up")},
+				"0001_users.down.sql": {Data: []byte("down")},
+				"0001_notes.up.sql":   {Data: []byte("up")},
+				"0001_notes.down.sql": {Data: []byte("down")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got: %+v, expected: %+v", got, tt.want)
+			}
+		})
+	}
+}
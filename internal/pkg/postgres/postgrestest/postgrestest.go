@@ -0,0 +1,45 @@
+// Package postgrestest sets up the Postgres DB for the tests which depend on it
+package postgrestest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/naughtygopher/goapp/internal/pkg/migrations"
+	"github.com/naughtygopher/goapp/schemas"
+)
+
+// DSNEnv is the environment variable with the DSN of the DB used for tests
+const DSNEnv = "GOAPP_TEST_POSTGRES_DSN"
+
+// NewPool returns a connection pool to the test DB, with all the migrations applied. The test is
+// skipped if DSNEnv is not set, and the pool is closed once the test is complete
+func NewPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", DSNEnv)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed connecting to postgres: %+v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := migrations.New(pool, schemas.Migrations(), "")
+	if err != nil {
+		t.Fatalf("failed loading migrations: %+v", err)
+	}
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("failed applying migrations: %+v", err)
+	}
+
+	return pool
+}
@@ -1,6 +1,7 @@
 package sysignals
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,24 +13,63 @@ var (
 	ErrSigQuit = errors.New("received terminal signal")
 )
 
+// QuitSignals are the signals which are considered as a request to quit the app
+var QuitSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGTSTP}
+
 // NotifyErrorOnQuit creates an error upon receiving any of the os signal, to quit the app.
 // The error is then pushed to the channel
 func NotifyErrorOnQuit(errs chan<- error, otherSignals ...syscall.Signal) {
+	NotifyErrorOnQuitWithForce(errs, nil, otherSignals...)
+}
+
+// NotifyErrorOnQuitWithForce is same as NotifyErrorOnQuit, except that it keeps listening after the
+// first signal. Every subsequent quit signal calls forceQuit, which can be used to exit immediately
+// rather than waiting for the graceful shutdown to complete. If forceQuit is nil, it returns after
+// the first signal
+func NotifyErrorOnQuitWithForce(errs chan<- error, forceQuit func(os.Signal), otherSignals ...syscall.Signal) {
+	signals := make([]os.Signal, 0, len(QuitSignals)+len(otherSignals))
+	signals = append(signals, QuitSignals...)
+	for _, oSignal := range otherSignals {
+		signals = append(signals, oSignal)
+	}
+
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt)
+	signal.Notify(interrupt, signals...)
+	defer signal.Stop(interrupt)
 
+	quitting := false
 	for signalType := range interrupt {
-		switch signalType {
-		case syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGTSTP:
-			errs <- errors.Wrapf(ErrSigQuit, "%v", signalType)
+		if quitting {
+			forceQuit(signalType)
+			continue
+		}
+
+		// not blocking on the channel, since it might already have an error & the app be quitting
+		select {
+		case errs <- errors.Wrapf(ErrSigQuit, "%v", signalType):
+		default:
+		}
+
+		if forceQuit == nil {
 			return
 		}
+		quitting = true
+	}
+}
+
+// Notify calls handler for every one of the signals received, until the context is done.
+// The handler is called sequentially, in the order of signals received
+func Notify(ctx context.Context, handler func(os.Signal), signals ...os.Signal) {
+	incoming := make(chan os.Signal, 1)
+	signal.Notify(incoming, signals...)
+	defer signal.Stop(incoming)
 
-		for _, oSignal := range otherSignals {
-			if oSignal == signalType {
-				errs <- errors.Wrapf(ErrSigQuit, "%v", signalType)
-				return
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-incoming:
+			handler(sig)
 		}
 	}
 }
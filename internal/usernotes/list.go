@@ -0,0 +1,135 @@
+package usernotes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// SortOrder is the order in which notes are listed. A '-' prefix denotes descending order
+type SortOrder string
+
+const (
+	SortUpdatedAtAsc  SortOrder = "updated_at"
+	SortUpdatedAtDesc SortOrder = "-updated_at"
+)
+
+// Descending returns true if the notes are to be listed in the descending order of update time
+func (so SortOrder) Descending() bool {
+	return strings.HasPrefix(string(so), "-")
+}
+
+func (so SortOrder) Validate() error {
+	switch so {
+	case SortUpdatedAtAsc, SortUpdatedAtDesc:
+		return nil
+	}
+	return errors.Validationf("unsupported sort order '%s'", so)
+}
+
+// NoteList is a single page of notes of a user
+type NoteList struct {
+	Notes []Note
+	// NextCursor is empty if there are no more pages
+	NextCursor string
+}
+
+// PageCursor has the keyset, (updated_at, id), of the last note of a page. It is the decoded
+// form of NoteList.NextCursor, which the store lists the notes after
+type PageCursor struct {
+	Sort      SortOrder `json:"s"`
+	UpdatedAt time.Time `json:"u"`
+	ID        string    `json:"i"`
+}
+
+func (pc *PageCursor) Encode() string {
+	b, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageCursor(token string, sort SortOrder) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	pc := new(PageCursor)
+	err = json.Unmarshal(b, pc)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	if pc.Sort != sort {
+		return nil, errors.Validation("cursor does not match the sort order")
+	}
+
+	// the ID is validated here, else a crafted cursor would fail in the datastore instead
+	_, err = uuid.Parse(pc.ID)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	return pc, nil
+}
+
+// ListNotes returns a page of notes of the user. The most recently updated notes are listed
+// first, unless a different sort order is provided
+func (un *UserNotes) ListNotes(ctx context.Context, userID string, cursor string, limit int, sort SortOrder) (*NoteList, error) {
+	if userID == "" {
+		return nil, errors.Validation("user ID is required")
+	}
+
+	if sort == "" {
+		sort = SortUpdatedAtDesc
+	}
+	err := sort.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultListLimit
+	} else if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	after, err := decodePageCursor(strings.TrimSpace(cursor), sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra is fetched to know if there's a next page
+	notes, err := un.store.ListNotes(ctx, userID, after, limit+1, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NoteList{
+		Notes: notes,
+	}
+	if len(notes) > limit {
+		result.Notes = notes[:limit]
+		last := result.Notes[limit-1]
+		result.NextCursor = (&PageCursor{
+			Sort:      sort,
+			UpdatedAt: last.UpdatedAt,
+			ID:        last.ID,
+		}).Encode()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package usernotes
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+func TestPageCursor(t *testing.T) {
+	expected := &PageCursor{
+		Sort:      SortUpdatedAtDesc,
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		ID:        "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b",
+	}
+
+	got, err := decodePageCursor(expected.Encode(), SortUpdatedAtDesc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %+v, expected: %+v", got, expected)
+	}
+
+	_, err = decodePageCursor(expected.Encode(), SortUpdatedAtAsc)
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error for cursor with mismatching sort order, got %v", err)
+	}
+
+	crafted := &PageCursor{Sort: SortUpdatedAtDesc, UpdatedAt: expected.UpdatedAt, ID: "1 OR 1=1"}
+	_, err = decodePageCursor(crafted.Encode(), SortUpdatedAtDesc)
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error for cursor with an invalid ID, got %v", err)
+	}
+
+	badTime := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-updated_at","u":"yesterday","i":"` + expected.ID + `"}`))
+	_, err = decodePageCursor(badTime, SortUpdatedAtDesc)
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error for cursor with an invalid time, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package usernotes
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// memstore is an in-memory, thread-safe, implementation of the notes store, meant for local
+// development and tests. Unlike Postgres, it has no access to the users. So it does not enforce
+// the foreign key of notes to users, i.e. notes can be saved for users who do not exist, and
+// notes are not deleted along with their user
+type memstore struct {
+	mutex sync.RWMutex
+	notes map[string]Note
+}
+
+func (ms *memstore) GetNoteByID(ctx context.Context, userID string, noteID string) (*Note, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	note, ok := ms.notes[noteID]
+	if !ok || note.Creator.ID != userID {
+		return nil, errors.NotFoundErr(ErrNoteNotFound, noteID)
+	}
+
+	return &note, nil
+}
+
+func (ms *memstore) SaveNote(ctx context.Context, note *Note) (string, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	note.ID = uuid.NewString()
+	stored := *note
+	stored.Creator = &users.User{ID: note.Creator.ID}
+	ms.notes[note.ID] = stored
+
+	return note.ID, nil
+}
+
+func (ms *memstore) UpdateNote(ctx context.Context, note *Note, version time.Time) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	existing, ok := ms.notes[note.ID]
+	if !ok || existing.Creator.ID != note.Creator.ID {
+		return errors.NotFoundErr(ErrNoteNotFound, note.ID)
+	}
+
+	if !existing.UpdatedAt.Equal(version) {
+		return errors.DuplicateErr(ErrNoteVersionConflict, note.ID)
+	}
+
+	if existing.Title == note.Title && existing.Content == note.Content {
+		// same as the DB trigger, the version changes only if there's an actual change
+		note.UpdatedAt = existing.UpdatedAt
+		return nil
+	}
+
+	updatedAt := time.Now().Truncate(time.Microsecond)
+	if !updatedAt.After(existing.UpdatedAt) {
+		updatedAt = existing.UpdatedAt.Add(time.Microsecond)
+	}
+
+	existing.Title = note.Title
+	existing.Content = note.Content
+	existing.UpdatedAt = updatedAt
+	ms.notes[note.ID] = existing
+	note.UpdatedAt = updatedAt
+
+	return nil
+}
+
+func (ms *memstore) DeleteNote(ctx context.Context, userID string, noteID string, version time.Time) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	existing, ok := ms.notes[noteID]
+	if !ok || existing.Creator.ID != userID {
+		return errors.NotFoundErr(ErrNoteNotFound, noteID)
+	}
+
+	if !version.IsZero() && !existing.UpdatedAt.Equal(version) {
+		return errors.DuplicateErr(ErrNoteVersionConflict, noteID)
+	}
+
+	delete(ms.notes, noteID)
+
+	return nil
+}
+
+func (ms *memstore) ListNotes(
+	ctx context.Context,
+	userID string,
+	after *PageCursor,
+	limit int,
+	order SortOrder,
+) ([]Note, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	notes := make([]Note, 0, limit)
+	for _, note := range ms.notes {
+		if note.Creator.ID != userID {
+			continue
+		}
+		if after != nil && !isAfter(order, &note, after.UpdatedAt, after.ID) {
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return isAfter(order, &notes[j], notes[i].UpdatedAt, notes[i].ID)
+	})
+
+	if len(notes) > limit {
+		notes = notes[:limit]
+	}
+
+	return notes, nil
+}
+
+// isAfter returns true if the note comes after the (updatedAt, id) keyset in the given sort order
+func isAfter(order SortOrder, note *Note, updatedAt time.Time, id string) bool {
+	cmp := note.UpdatedAt.Compare(updatedAt)
+	if cmp == 0 {
+		cmp = strings.Compare(note.ID, id)
+	}
+
+	if order.Descending() {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+func NewMemoryStore() store {
+	return &memstore{
+		notes: map[string]Note{},
+	}
+}
@@ -2,9 +2,12 @@ package usernotes
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/goapp/internal/users"
@@ -17,6 +20,10 @@ type pgstore struct {
 }
 
 func (ps *pgstore) GetNoteByID(ctx context.Context, userID string, noteID string) (*Note, error) {
+	if uuid.Validate(userID) != nil || uuid.Validate(noteID) != nil {
+		return nil, errors.NotFoundErr(ErrNoteNotFound, noteID)
+	}
+
 	query, args, err := ps.qbuilder.Select(
 		"title",
 		"content",
@@ -50,6 +57,9 @@ func (ps *pgstore) GetNoteByID(ctx context.Context, userID string, noteID string
 		&usernote.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.NotFoundErr(ErrNoteNotFound, noteID)
+		}
 		return nil, errors.Wrap(err, "failed getting user note")
 	}
 
@@ -57,7 +67,7 @@ func (ps *pgstore) GetNoteByID(ctx context.Context, userID string, noteID string
 }
 
 func (ps *pgstore) SaveNote(ctx context.Context, note *Note) (string, error) {
-	noteID := ps.newNoteID()
+	note.ID = ps.newNoteID()
 	query, args, err := ps.qbuilder.Insert(
 		ps.tableName,
 	).Columns(
@@ -65,11 +75,15 @@ func (ps *pgstore) SaveNote(ctx context.Context, note *Note) (string, error) {
 		"title",
 		"content",
 		"user_id",
+		"created_at",
+		"updated_at",
 	).Values(
 		note.ID,
 		note.Title,
 		note.Content,
 		note.Creator.ID,
+		note.CreatedAt,
+		note.UpdatedAt,
 	).ToSql()
 	if err != nil {
 		return "", errors.Wrap(err, "failed preparing query")
@@ -80,7 +94,163 @@ func (ps *pgstore) SaveNote(ctx context.Context, note *Note) (string, error) {
 		return "", errors.Wrap(err, "failed storing note")
 	}
 
-	return noteID, nil
+	return note.ID, nil
+}
+
+func (ps *pgstore) UpdateNote(ctx context.Context, note *Note, version time.Time) error {
+	query, args, err := ps.qbuilder.Update(
+		ps.tableName,
+	).SetMap(map[string]any{
+		"title":   note.Title,
+		"content": note.Content,
+	}).Where(
+		squirrel.Eq{
+			"id":         note.ID,
+			"user_id":    note.Creator.ID,
+			"updated_at": version,
+		},
+	).Suffix(
+		"RETURNING updated_at",
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	err = ps.pqdriver.QueryRow(ctx, query, args...).Scan(&note.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ps.versionMismatchErr(ctx, note.Creator.ID, note.ID)
+		}
+		return errors.Wrap(err, "failed updating note")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) DeleteNote(ctx context.Context, userID string, noteID string, version time.Time) error {
+	if uuid.Validate(userID) != nil || uuid.Validate(noteID) != nil {
+		return errors.NotFoundErr(ErrNoteNotFound, noteID)
+	}
+
+	where := squirrel.Eq{
+		"id":      noteID,
+		"user_id": userID,
+	}
+	if !version.IsZero() {
+		where["updated_at"] = version
+	}
+
+	query, args, err := ps.qbuilder.Delete(
+		ps.tableName,
+	).Where(
+		where,
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed deleting note")
+	}
+
+	if tag.RowsAffected() == 0 {
+		if version.IsZero() {
+			return errors.NotFoundErr(ErrNoteNotFound, noteID)
+		}
+		return ps.versionMismatchErr(ctx, userID, noteID)
+	}
+
+	return nil
+}
+
+// versionMismatchErr is used when a conditional write affects no rows, to differentiate between
+// the note not existing and the note having been modified
+func (ps *pgstore) versionMismatchErr(ctx context.Context, userID string, noteID string) error {
+	_, err := ps.GetNoteByID(ctx, userID, noteID)
+	if err != nil {
+		return err
+	}
+
+	return errors.DuplicateErr(ErrNoteVersionConflict, noteID)
+}
+
+func (ps *pgstore) ListNotes(
+	ctx context.Context,
+	userID string,
+	after *PageCursor,
+	limit int,
+	sort SortOrder,
+) ([]Note, error) {
+	if uuid.Validate(userID) != nil {
+		return []Note{}, nil
+	}
+
+	direction, operator := "ASC", ">"
+	if sort.Descending() {
+		direction, operator = "DESC", "<"
+	}
+
+	qbuilder := ps.qbuilder.Select(
+		"id",
+		"title",
+		"content",
+		"created_at",
+		"updated_at",
+	).From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"user_id": userID},
+	)
+
+	if after != nil {
+		// row value comparison, so that notes updated at the same time are paginated by ID
+		qbuilder = qbuilder.Where(
+			fmt.Sprintf("(updated_at, id) %s (?, ?)", operator),
+			after.UpdatedAt,
+			after.ID,
+		)
+	}
+
+	query, args, err := qbuilder.OrderBy(
+		"updated_at "+direction,
+		"id "+direction,
+	).Limit(
+		uint64(limit),
+	).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	rows, err := ps.pqdriver.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing user notes")
+	}
+	defer rows.Close()
+
+	creator := &users.User{ID: userID}
+	notes := make([]Note, 0, limit)
+	for rows.Next() {
+		note := Note{Creator: creator}
+		err = rows.Scan(
+			&note.ID,
+			&note.Title,
+			&note.Content,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading user note")
+		}
+		notes = append(notes, note)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing user notes")
+	}
+
+	return notes, nil
 }
 
 func (ps *pgstore) newNoteID() string {
@@ -0,0 +1,49 @@
+package usernotes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/naughtygopher/goapp/internal/pkg/postgres/postgrestest"
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/usernotes/usernotestest"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestMemoryStore(t *testing.T) {
+	usernotestest.RunStoreSuite(
+		t,
+		func(t *testing.T) usernotes.Store {
+			return usernotes.NewMemoryStore()
+		},
+		func(t *testing.T) string {
+			return uuid.NewString()
+		},
+	)
+}
+
+// TestPostgresStore runs only if GOAPP_TEST_POSTGRES_DSN is set
+func TestPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	pool := postgrestest.NewPool(t)
+
+	userStore := users.NewPostgresStore(pool, "users")
+	usernotestest.RunStoreSuite(
+		t,
+		func(t *testing.T) usernotes.Store {
+			return usernotes.NewPostgresStore(pool, "user_notes")
+		},
+		func(t *testing.T) string {
+			id, err := userStore.SaveUser(ctx, &users.User{
+				FullName: "Note Creator",
+				Email:    uuid.NewString() + "@example.com",
+			})
+			if err != nil {
+				t.Fatalf("failed creating user: %+v", err)
+			}
+			return id
+		},
+	)
+}
@@ -3,6 +3,7 @@ package usernotes
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +11,12 @@ import (
 	"github.com/naughtygopher/goapp/internal/users"
 )
 
+var (
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrNoteVersionConflict is returned when the note was modified after the version provided
+	ErrNoteVersionConflict = errors.New("note has been modified since it was last read")
+)
+
 type Note struct {
 	ID        string
 	Title     string
@@ -40,6 +47,35 @@ func (note *Note) ValidateForCreate() error {
 	return nil
 }
 
+// ValidateForUpdate runs the validation required for when an existing note is being updated
+func (note *Note) ValidateForUpdate() error {
+	if note == nil {
+		return errors.Validation("empty note")
+	}
+
+	if note.ID == "" {
+		return errors.Validation("note ID cannot be empty")
+	}
+
+	return note.ValidateForCreate()
+}
+
+// Version returns an opaque token which changes every time the note is updated. The
+// updated_at column, maintained by the DB trigger, is used as the version
+func (note *Note) Version() string {
+	return strconv.FormatInt(note.UpdatedAt.UnixMicro(), 10)
+}
+
+// ParseVersion parses the token returned by Note.Version
+func ParseVersion(version string) (time.Time, error) {
+	micros, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.ValidationErr(err, "invalid note version")
+	}
+
+	return time.UnixMicro(micros), nil
+}
+
 func (note *Note) Sanitize() {
 	note.Title = strings.TrimSpace(note.Title)
 	note.Content = strings.TrimSpace(note.Content)
@@ -48,6 +84,33 @@ func (note *Note) Sanitize() {
 type store interface {
 	GetNoteByID(ctx context.Context, userID string, noteID string) (*Note, error)
 	SaveNote(ctx context.Context, note *Note) (string, error)
+	// UpdateNote updates the note only if it was last updated at 'version'. The note's UpdatedAt is
+	// set to the new version on success
+	UpdateNote(ctx context.Context, note *Note, version time.Time) error
+	// DeleteNote deletes the note only if it was last updated at 'version', or irrespective of
+	// it if the version is zero
+	DeleteNote(ctx context.Context, userID string, noteID string, version time.Time) error
+	ListNotes(ctx context.Context, userID string, after *PageCursor, limit int, sort SortOrder) ([]Note, error)
+}
+
+// Store allows notes datastores to be implemented in other packages, every implementation should
+// pass usernotestest.RunStoreSuite
+type Store = store
+
+// NoteUpdate holds the fields of a note which can be updated. Only the non-nil fields are applied
+type NoteUpdate struct {
+	Title   *string
+	Content *string
+}
+
+// Apply overwrites the respective fields of note with all the non-nil fields of the update
+func (nu *NoteUpdate) Apply(note *Note) {
+	if nu.Title != nil {
+		note.Title = *nu.Title
+	}
+	if nu.Content != nil {
+		note.Content = *nu.Content
+	}
 }
 
 type UserNotes struct {
@@ -60,8 +123,10 @@ func (un *UserNotes) SaveNote(ctx context.Context, note *Note) (*Note, error) {
 		return nil, err
 	}
 
-	note.CreatedAt = time.Now()
-	note.UpdatedAt = time.Now()
+	// truncated to the precision of the datastore, so that the version is the same as what's stored
+	now := time.Now().Truncate(time.Microsecond)
+	note.CreatedAt = now
+	note.UpdatedAt = now
 	note.ID, err = un.store.SaveNote(ctx, note)
 	if err != nil {
 		return nil, err
@@ -71,9 +136,61 @@ func (un *UserNotes) SaveNote(ctx context.Context, note *Note) (*Note, error) {
 }
 
 func (un *UserNotes) GetNoteByID(ctx context.Context, userID string, noteID string) (*Note, error) {
+	if userID == "" || noteID == "" {
+		return nil, errors.Validation("user ID and note ID are required")
+	}
+
 	return un.store.GetNoteByID(ctx, userID, noteID)
 }
 
+// UpdateNote partially updates the note. If version is not zero, the update is done only if
+// the note was not modified since, else ErrNoteVersionConflict is returned
+func (un *UserNotes) UpdateNote(
+	ctx context.Context,
+	userID string,
+	noteID string,
+	nu *NoteUpdate,
+	version time.Time,
+) (*Note, error) {
+	if nu == nil {
+		return nil, errors.Validation("no update provided")
+	}
+
+	note, err := un.GetNoteByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// conflicts are reported as 'duplicate' errors, which map to HTTP status 409
+	if !version.IsZero() && !note.UpdatedAt.Equal(version) {
+		return nil, errors.DuplicateErr(ErrNoteVersionConflict, noteID)
+	}
+
+	nu.Apply(note)
+	err = note.ValidateForUpdate()
+	if err != nil {
+		return nil, err
+	}
+
+	// the version read above is used, so that an update between the read and now is not lost
+	err = un.store.UpdateNote(ctx, note, note.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// DeleteNote deletes the note. If version is not zero, the note is deleted only if it was not
+// modified since, else ErrNoteVersionConflict is returned
+func (un *UserNotes) DeleteNote(ctx context.Context, userID string, noteID string, version time.Time) error {
+	if userID == "" || noteID == "" {
+		return errors.Validation("user ID and note ID are required")
+	}
+
+	return un.store.DeleteNote(ctx, userID, noteID, version)
+}
+
 func NewService(store store) *UserNotes {
 	return &UserNotes{
 		store: store,
@@ -0,0 +1,92 @@
+package usernotes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+func TestNote_Version(t *testing.T) {
+	note := &Note{UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)}
+
+	version, err := ParseVersion(note.Version())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !version.Equal(note.UpdatedAt) {
+		t.Errorf("expected version %s, got %s", note.UpdatedAt, version)
+	}
+
+	_, err = ParseVersion("not a version")
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func saveTestNote(t *testing.T, un *UserNotes) *Note {
+	t.Helper()
+	note, err := un.SaveNote(context.Background(), &Note{
+		Title:   "title",
+		Content: "content",
+		Creator: &users.User{ID: "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return note
+}
+
+func TestUserNotes_UpdateNoteVersion(t *testing.T) {
+	ctx := context.Background()
+	un := NewService(NewMemoryStore())
+	note := saveTestNote(t, un)
+	userID := note.Creator.ID
+	stale := note.UpdatedAt
+
+	title := "updated"
+	updated, err := un.UpdateNote(ctx, userID, note.ID, &NoteUpdate{Title: &title}, stale)
+	if err != nil {
+		t.Fatalf("expected the update with the current version to succeed, got %v", err)
+	}
+	if !updated.UpdatedAt.After(stale) {
+		t.Fatalf("expected the version to change after the update")
+	}
+
+	title = "stale"
+	_, err = un.UpdateNote(ctx, userID, note.ID, &NoteUpdate{Title: &title}, stale)
+	if !errors.Is(err, ErrNoteVersionConflict) || errors.Type(err) != errors.TypeDuplicate {
+		t.Fatalf("expected a version conflict for a stale version, got %v", err)
+	}
+
+	// a zero version updates irrespective of the current version
+	_, err = un.UpdateNote(ctx, userID, note.ID, &NoteUpdate{Title: &title}, time.Time{})
+	if err != nil {
+		t.Fatalf("expected the unconditional update to succeed, got %v", err)
+	}
+}
+
+func TestUserNotes_DeleteNoteVersion(t *testing.T) {
+	ctx := context.Background()
+	un := NewService(NewMemoryStore())
+	note := saveTestNote(t, un)
+	userID := note.Creator.ID
+
+	err := un.DeleteNote(ctx, userID, note.ID, note.UpdatedAt.Add(-time.Second))
+	if !errors.Is(err, ErrNoteVersionConflict) {
+		t.Fatalf("expected a version conflict for a stale version, got %v", err)
+	}
+
+	err = un.DeleteNote(ctx, userID, note.ID, note.UpdatedAt)
+	if err != nil {
+		t.Fatalf("expected the delete with the current version to succeed, got %v", err)
+	}
+
+	_, err = un.GetNoteByID(ctx, userID, note.ID)
+	if !errors.Is(err, ErrNoteNotFound) {
+		t.Fatalf("expected the note to be deleted, got %v", err)
+	}
+}
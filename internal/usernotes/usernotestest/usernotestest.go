@@ -0,0 +1,248 @@
+// Package usernotestest has the conformance suite which every implementation of the usernotes
+// store must pass, so that all the datastores behave exactly the same
+package usernotestest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/usernotes"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// RunStoreSuite runs the conformance suite against the store returned by newStore. newUserID
+// should return the ID of a user who exists in the backing datastore, since notes can only be
+// created for existing users
+func RunStoreSuite(
+	t *testing.T,
+	newStore func(t *testing.T) usernotes.Store,
+	newUserID func(t *testing.T) string,
+) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, st usernotes.Store, userID string)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"GetNotFound", testGetNotFound},
+		{"Update", testUpdate},
+		{"UpdateConflict", testUpdateConflict},
+		{"Delete", testDelete},
+		{"List", testList},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t), newUserID(t))
+		})
+	}
+}
+
+func assertType(t *testing.T, err error, etype int, name string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected %s error, got nil", name)
+	}
+	if errors.TypeInt(err) != etype {
+		t.Fatalf("expected %s error, got: %+v", name, err)
+	}
+}
+
+// saveNote saves a note the same way the service does, i.e. timestamps set by the caller
+func saveNote(t *testing.T, st usernotes.Store, userID string) *usernotes.Note {
+	t.Helper()
+
+	now := time.Now().Truncate(time.Microsecond)
+	note := &usernotes.Note{
+		Title:     "title " + uuid.NewString(),
+		Content:   "content",
+		Creator:   &users.User{ID: userID},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := st.SaveNote(context.Background(), note)
+	if err != nil {
+		t.Fatalf("SaveNote: %+v", err)
+	}
+	if id == "" || id != note.ID {
+		t.Fatalf("SaveNote returned ID '%s', note has ID '%s'", id, note.ID)
+	}
+
+	return note
+}
+
+func testSaveAndGet(t *testing.T, st usernotes.Store, userID string) {
+	note := saveNote(t, st, userID)
+
+	got, err := st.GetNoteByID(context.Background(), userID, note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID: %+v", err)
+	}
+
+	if got.ID != note.ID ||
+		got.Title != note.Title ||
+		got.Content != note.Content ||
+		got.Creator == nil || got.Creator.ID != userID ||
+		!got.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Fatalf("got: %+v, expected: %+v", got, note)
+	}
+}
+
+func testGetNotFound(t *testing.T, st usernotes.Store, userID string) {
+	ctx := context.Background()
+
+	_, err := st.GetNoteByID(ctx, userID, uuid.NewString())
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	_, err = st.GetNoteByID(ctx, userID, "not-a-uuid")
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	// a note is not visible to other users
+	note := saveNote(t, st, userID)
+	_, err = st.GetNoteByID(ctx, uuid.NewString(), note.ID)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testUpdate(t *testing.T, st usernotes.Store, userID string) {
+	ctx := context.Background()
+	note := saveNote(t, st, userID)
+	version := note.UpdatedAt
+
+	note.Title = "updated title"
+	err := st.UpdateNote(ctx, note, version)
+	if err != nil {
+		t.Fatalf("UpdateNote: %+v", err)
+	}
+	if note.UpdatedAt.Equal(version) {
+		t.Fatal("expected the version to change after update")
+	}
+
+	got, err := st.GetNoteByID(ctx, userID, note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID: %+v", err)
+	}
+	if got.Title != note.Title || !got.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Fatalf("got: %+v, expected: %+v", got, note)
+	}
+
+	missing := *note
+	missing.ID = uuid.NewString()
+	err = st.UpdateNote(ctx, &missing, note.UpdatedAt)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testUpdateConflict(t *testing.T, st usernotes.Store, userID string) {
+	ctx := context.Background()
+	note := saveNote(t, st, userID)
+	stale := note.UpdatedAt
+
+	note.Content = "first update"
+	err := st.UpdateNote(ctx, note, stale)
+	if err != nil {
+		t.Fatalf("UpdateNote: %+v", err)
+	}
+
+	note.Content = "second update, with a stale version"
+	err = st.UpdateNote(ctx, note, stale)
+	assertType(t, err, errors.TypeDuplicate.Int(), "conflict")
+	if !errors.Is(err, usernotes.ErrNoteVersionConflict) {
+		t.Fatalf("expected ErrNoteVersionConflict, got: %+v", err)
+	}
+
+	got, err := st.GetNoteByID(ctx, userID, note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID: %+v", err)
+	}
+	if got.Content != "first update" {
+		t.Fatalf("stale update was applied: %+v", got)
+	}
+}
+
+func testDelete(t *testing.T, st usernotes.Store, userID string) {
+	ctx := context.Background()
+	note := saveNote(t, st, userID)
+
+	err := st.DeleteNote(ctx, userID, note.ID, note.UpdatedAt.Add(-time.Second))
+	assertType(t, err, errors.TypeDuplicate.Int(), "conflict")
+
+	err = st.DeleteNote(ctx, userID, note.ID, note.UpdatedAt)
+	if err != nil {
+		t.Fatalf("DeleteNote: %+v", err)
+	}
+
+	_, err = st.GetNoteByID(ctx, userID, note.ID)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	err = st.DeleteNote(ctx, userID, note.ID, time.Time{})
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testList(t *testing.T, st usernotes.Store, userID string) {
+	ctx := context.Background()
+	svc := usernotes.NewService(st)
+
+	created := make(map[string]struct{})
+	for range 5 {
+		note := saveNote(t, st, userID)
+		created[note.ID] = struct{}{}
+	}
+
+	for _, order := range []usernotes.SortOrder{usernotes.SortUpdatedAtAsc, usernotes.SortUpdatedAtDesc} {
+		t.Run(string(order), func(t *testing.T) {
+			listed := make([]usernotes.Note, 0, len(created))
+			cursor := ""
+			for page := 0; ; page++ {
+				if page > len(created) {
+					t.Fatal("pagination did not end")
+				}
+
+				list, err := svc.ListNotes(ctx, userID, cursor, 2, order)
+				if err != nil {
+					t.Fatalf("ListNotes: %+v", err)
+				}
+				listed = append(listed, list.Notes...)
+				if list.NextCursor == "" {
+					break
+				}
+				cursor = list.NextCursor
+			}
+
+			if len(listed) != len(created) {
+				t.Fatalf("expected %d notes, got %d", len(created), len(listed))
+			}
+
+			seen := make(map[string]struct{}, len(listed))
+			for i := range listed {
+				if _, ok := created[listed[i].ID]; !ok {
+					t.Fatalf("unexpected note listed: %+v", listed[i])
+				}
+				if _, ok := seen[listed[i].ID]; ok {
+					t.Fatalf("note listed more than once: %+v", listed[i])
+				}
+				seen[listed[i].ID] = struct{}{}
+
+				if i == 0 {
+					continue
+				}
+				prev, curr := listed[i-1].UpdatedAt, listed[i].UpdatedAt
+				if (order.Descending() && curr.After(prev)) || (!order.Descending() && curr.Before(prev)) {
+					t.Fatalf("notes not in %s order: %+v, %+v", order, listed[i-1], listed[i])
+				}
+			}
+		})
+	}
+
+	list, err := svc.ListNotes(ctx, uuid.NewString(), "", 0, "")
+	if err != nil {
+		t.Fatalf("ListNotes: %+v", err)
+	}
+	if len(list.Notes) != 0 {
+		t.Fatalf("expected no notes for another user, got %d", len(list.Notes))
+	}
+}
@@ -0,0 +1,86 @@
+package users
+
+import (
+	"context"
+	"slices"
+)
+
+// EmailCollision is a set of users whose emails are the same once normalized
+type EmailCollision struct {
+	// Email is the normalized email
+	Email   string
+	UserIDs []string
+	// Emails are the emails of the users, in the same order as UserIDs
+	Emails []string
+}
+
+// EmailBackfill is the result of normalizing the emails of the existing users
+type EmailBackfill struct {
+	Scanned int
+	// Normalized is the number of users whose email was (or would be, for a dry run) normalized
+	Normalized int
+	// Collisions are not normalized, and should be resolved manually
+	Collisions []EmailCollision
+}
+
+// BackfillEmails normalizes the emails of all the existing users, which were saved before emails
+// were normalized. Users whose emails collide once normalized are not updated, and are reported
+// instead, so they can be resolved before the case-insensitive unique index is created.
+// Nothing is updated for a dry run
+func (us *Users) BackfillEmails(ctx context.Context, dryRun bool) (*EmailBackfill, error) {
+	type account struct {
+		id    string
+		email string
+	}
+
+	result := &EmailBackfill{
+		Collisions: make([]EmailCollision, 0),
+	}
+	accounts := make(map[string][]account)
+	// the backfill is run before the migration creating the case-insensitive unique index, so it
+	// should not depend on any of the columns added after
+	err := us.store.UserEmails(ctx, func(userID string, email string) error {
+		result.Scanned++
+		normalized := NormalizeEmail(email)
+		accounts[normalized] = append(accounts[normalized], account{id: userID, email: email})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]string, 0, len(accounts))
+	for email := range accounts {
+		emails = append(emails, email)
+	}
+	slices.Sort(emails)
+
+	for _, email := range emails {
+		accs := accounts[email]
+		if len(accs) > 1 {
+			collision := EmailCollision{Email: email}
+			for _, acc := range accs {
+				collision.UserIDs = append(collision.UserIDs, acc.id)
+				collision.Emails = append(collision.Emails, acc.email)
+			}
+			result.Collisions = append(result.Collisions, collision)
+			continue
+		}
+
+		if accs[0].email == email {
+			continue
+		}
+
+		result.Normalized++
+		if dryRun {
+			continue
+		}
+
+		err = us.store.UpdateUserEmail(ctx, accs[0].id, email)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package users
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUsers_BackfillEmails(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStore()
+	svc := NewService(st, nil)
+
+	// saved directly to the store, as users were before emails were normalized
+	legacy := &User{FullName: "Jane Doe", Email: "Jane@Example.com"}
+	_, err := st.SaveUser(ctx, legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalized := &User{FullName: "John Doe", Email: "john@example.com"}
+	_, err = st.SaveUser(ctx, normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.BackfillEmails(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 2 || result.Normalized != 1 {
+		t.Fatalf("expected 2 scanned & 1 normalized, got %+v", result)
+	}
+	user, err := st.GetUserByID(ctx, legacy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != legacy.Email {
+		t.Fatalf("expected the email to be unchanged for a dry run, got %s", user.Email)
+	}
+
+	_, err = svc.BackfillEmails(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err = st.GetUserByID(ctx, legacy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "jane@example.com" {
+		t.Fatalf("expected the email to be normalized, got %s", user.Email)
+	}
+}
@@ -0,0 +1,148 @@
+package users
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
+)
+
+// RowStatus is the status of a single user (row) in a bulk operation
+type RowStatus string
+
+const (
+	RowInserted  RowStatus = "inserted"
+	RowDuplicate RowStatus = "duplicate"
+	RowInvalid   RowStatus = "invalid"
+)
+
+// RowResult is the result of a single user (row) in a bulk operation
+type RowResult struct {
+	// Row is the index of the user in the list, starting with 0
+	Row    int
+	ID     string
+	Email  string
+	Status RowStatus
+	Error  string `json:",omitempty"`
+}
+
+// BulkCreateResult is the result of saving users in bulk
+type BulkCreateResult struct {
+	// Accepted is the number of users received
+	Accepted int
+	Inserted int
+	Failed   int
+	// Errors has the details of every user which failed to be saved
+	Errors []RowError
+}
+
+// RowError is the error of a single user (row) in a bulk operation
+type RowError struct {
+	// Row is the index of the user in the list, starting with 0
+	Row    int
+	Email  string
+	Status RowStatus
+	Error  string
+}
+
+// BulkCreateUsers saves all the valid users, and returns the result of every user in the same order.
+// Unlike an all or nothing insert, invalid or duplicate users do not prevent the rest from being saved.
+// IDs are assigned to the users which do not have one
+func (us *Users) BulkCreateUsers(ctx context.Context, users []User) ([]RowResult, error) {
+	results := make([]RowResult, len(users))
+	valid := make([]User, 0, len(users))
+	validRows := make([]int, 0, len(users))
+
+	for idx := range users {
+		user := &users[idx]
+		user.Sanitize()
+		if user.ID == "" {
+			user.ID = uuid.NewString()
+		}
+
+		results[idx] = RowResult{
+			Row:   idx,
+			ID:    user.ID,
+			Email: user.Email,
+		}
+
+		err := user.ValidateForCreate()
+		if err != nil {
+			msg, _ := errors.Message(err)
+			results[idx].Status = RowInvalid
+			results[idx].Error = msg
+			continue
+		}
+
+		valid = append(valid, *user)
+		validRows = append(validRows, idx)
+	}
+
+	statuses, err := us.store.BulkSaveUserPartial(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, status := range statuses {
+		result := &results[validRows[idx]]
+		result.Status = status
+		switch status {
+		case RowDuplicate:
+			msg, _ := errors.Message(ErrUserEmailAlreadyExists)
+			result.Error = msg
+		case RowInvalid:
+			result.Error = "invalid user"
+		}
+	}
+
+	return results, nil
+}
+
+func (us *Users) bulkCreateJob(ctx context.Context, job *jobqueue.Job, users []User) (any, error) {
+	rowResults, err := us.BulkCreateUsers(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkCreateResult{
+		Accepted: len(users),
+		Errors:   make([]RowError, 0),
+	}
+	for _, rres := range rowResults {
+		if rres.Status == RowInserted {
+			result.Inserted++
+			continue
+		}
+
+		result.Failed++
+		result.Errors = append(result.Errors, RowError{
+			Row:    rres.Row,
+			Email:  rres.Email,
+			Status: rres.Status,
+			Error:  rres.Error,
+		})
+	}
+
+	return result, nil
+}
+
+// ReadBulkCreateJob returns the job created by AsyncCreateUsers. The result of the job
+// is a BulkCreateResult, and is available only after the job has succeeded
+func (us *Users) ReadBulkCreateJob(ctx context.Context, jobID string) (*jobqueue.Job, error) {
+	if us.jobs == nil {
+		return nil, errors.New("job queue is not configured")
+	}
+
+	job, err := us.jobs.Job(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Kind != JobKindBulkCreate {
+		return nil, errors.NotFoundErr(jobqueue.ErrJobNotFound, jobID)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,97 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
+)
+
+func newBulkUser() User {
+	return User{
+		FullName:       "Name",
+		Email:          uuid.NewString() + "@example.com",
+		Phone:          "+91 1234567890",
+		ContactAddress: "Addr line 1, line 2, City, PIN, Country",
+	}
+}
+
+func TestUsers_AsyncCreateUsers(t *testing.T) {
+	ctx := context.Background()
+	jobs := jobqueue.New(jobqueue.NewMemoryStore(), jobqueue.Options{
+		Workers:      1,
+		PollInterval: time.Millisecond * 10,
+	})
+	svc := NewService(NewMemoryStore(), jobs)
+	err := jobs.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = jobs.Shutdown(ctx) }()
+
+	existing := newBulkUser()
+	_, err = svc.CreateUser(ctx, &existing)
+	if err != nil {
+		t.Fatalf("CreateUser: %+v", err)
+	}
+
+	duplicate := newBulkUser()
+	duplicate.Email = existing.Email
+	invalid := newBulkUser()
+	invalid.Email = "not-an-email"
+	jobID, err := svc.AsyncCreateUsers(ctx, []User{newBulkUser(), duplicate, newBulkUser(), invalid})
+	if err != nil {
+		t.Fatalf("AsyncCreateUsers: %+v", err)
+	}
+
+	var job *jobqueue.Job
+	for deadline := time.Now().Add(time.Second * 5); time.Now().Before(deadline); time.Sleep(time.Millisecond * 10) {
+		job, err = svc.ReadBulkCreateJob(ctx, jobID)
+		if err != nil {
+			t.Fatalf("ReadBulkCreateJob: %+v", err)
+		}
+		if job.Status == jobqueue.StatusSucceeded || job.Status == jobqueue.StatusDead {
+			break
+		}
+	}
+
+	if job.Status != jobqueue.StatusSucceeded {
+		t.Fatalf("expected job to succeed, got status %s: %s", job.Status, job.LastError)
+	}
+
+	result := BulkCreateResult{}
+	err = json.Unmarshal(job.Result, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an invalid user does not reject the rest, and is reported along with the duplicate
+	if result.Accepted != 4 || result.Inserted != 2 || result.Failed != 2 {
+		t.Errorf("expected 4 accepted, 2 inserted & 2 failed, got %+v", result)
+	}
+	if len(result.Errors) != 2 ||
+		result.Errors[0].Row != 1 || result.Errors[0].Status != RowDuplicate ||
+		result.Errors[1].Row != 3 || result.Errors[1].Status != RowInvalid {
+		t.Errorf("expected a duplicate for row 1 & invalid for row 3, got %+v", result.Errors)
+	}
+}
+
+func TestUsers_BulkCreateUsers_CanonicalID(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), nil)
+
+	user := newBulkUser()
+	user.ID = "0190B6C4-43D5-7D6B-9D5B-1F3F3C1E2A9B"
+	results, err := svc.BulkCreateUsers(ctx, []User{user})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].Status != RowInserted || results[0].ID != "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b" {
+		t.Fatalf("expected the user to be inserted with the canonical ID, got %+v", results[0])
+	}
+}
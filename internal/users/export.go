@@ -0,0 +1,136 @@
+package users
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+// ExportFormat is the format in which users are exported
+type ExportFormat string
+
+const (
+	ExportNDJSON ExportFormat = "ndjson"
+	ExportCSV    ExportFormat = "csv"
+)
+
+func (ef ExportFormat) Validate() error {
+	switch ef {
+	case ExportNDJSON, ExportCSV:
+		return nil
+	}
+	return errors.Validationf("unsupported export format '%s'", ef)
+}
+
+// ContentType returns the MIME type of the format
+func (ef ExportFormat) ContentType() string {
+	if ef == ExportCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// exportCSVHeader is the header of a CSV export, the columns are in the same order as the
+// values in ExportWriter.Write. It is compatible with the CSV import
+var exportCSVHeader = []string{
+	"id",
+	"full_name",
+	"email",
+	"phone",
+	"contact_address",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+// ExportWriter writes users one at a time in the export format. Writes are buffered,
+// and Flush should be called once done
+type ExportWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func (ew *ExportWriter) Write(user *User) error {
+	var err error
+	if ew.csv != nil {
+		err = ew.csv.Write([]string{
+			user.ID,
+			user.FullName,
+			user.Email,
+			user.Phone,
+			user.ContactAddress,
+			string(user.Status),
+			user.CreatedAt.Format(time.RFC3339Nano),
+			user.UpdatedAt.Format(time.RFC3339Nano),
+		})
+	} else {
+		err = ew.json.Encode(user)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed writing user")
+	}
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer
+func (ew *ExportWriter) Flush() error {
+	if ew.csv == nil {
+		return nil
+	}
+
+	ew.csv.Flush()
+	err := ew.csv.Error()
+	if err != nil {
+		return errors.Wrap(err, "failed writing users")
+	}
+
+	return nil
+}
+
+// NewExportWriter returns a writer for the format. For CSV, the header is written right away
+func NewExportWriter(w io.Writer, format ExportFormat) (*ExportWriter, error) {
+	err := format.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if format != ExportCSV {
+		return &ExportWriter{json: json.NewEncoder(w)}, nil
+	}
+
+	ew := &ExportWriter{csv: csv.NewWriter(w)}
+	err = ew.csv.Write(exportCSVHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed writing CSV header")
+	}
+
+	return ew, nil
+}
+
+// ExportUsers calls each for every user matching the filters, in the sort order. Unlike ListUsers,
+// all the users are read with a single query (a server-side cursor for Postgres) so memory stays
+// flat regardless of the number of users. Limit is ignored, and the export starts after the Cursor
+// if provided. The export stops at the first error returned by each
+func (us *Users) ExportUsers(ctx context.Context, opts *ListOptions, each func(*User) error) error {
+	if opts == nil {
+		opts = new(ListOptions)
+	}
+
+	opts.Sanitize()
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+
+	after, err := decodePageCursor(opts.Cursor, opts.Sort)
+	if err != nil {
+		return err
+	}
+
+	return us.store.ExportUsers(ctx, opts, after, each)
+}
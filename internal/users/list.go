@@ -0,0 +1,208 @@
+package users
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// SortOrder is the order in which users are listed. A '-' prefix denotes descending order
+type SortOrder string
+
+const (
+	SortCreatedAtAsc  SortOrder = "created_at"
+	SortCreatedAtDesc SortOrder = "-created_at"
+	SortEmailAsc      SortOrder = "email"
+	SortEmailDesc     SortOrder = "-email"
+)
+
+// Column returns the column used for sorting and if the order is descending
+func (so SortOrder) Column() (string, bool) {
+	desc := strings.HasPrefix(string(so), "-")
+	return strings.TrimPrefix(string(so), "-"), desc
+}
+
+func (so SortOrder) Validate() error {
+	switch so {
+	case SortCreatedAtAsc, SortCreatedAtDesc, SortEmailAsc, SortEmailDesc:
+		return nil
+	}
+	return errors.Validationf("unsupported sort order '%s'", so)
+}
+
+// ListFilter has all the filters supported while listing users. Zero values are ignored
+type ListFilter struct {
+	// EmailDomain matches the domain of the email, i.e. the part after '@'
+	EmailDomain string
+	// NamePrefix is a case insensitive prefix match on the full name
+	NamePrefix string
+	// CreatedAfter is inclusive
+	CreatedAfter time.Time
+	// CreatedBefore is exclusive
+	CreatedBefore time.Time
+}
+
+func (lf *ListFilter) Sanitize() {
+	lf.EmailDomain = strings.TrimPrefix(strings.TrimSpace(lf.EmailDomain), "@")
+	lf.NamePrefix = strings.TrimSpace(lf.NamePrefix)
+}
+
+func (lf *ListFilter) Validate() error {
+	if !lf.CreatedAfter.IsZero() && !lf.CreatedBefore.IsZero() && !lf.CreatedBefore.After(lf.CreatedAfter) {
+		return errors.Validation("created before should be later than created after")
+	}
+	return nil
+}
+
+// ListOptions are the options for listing users one page at a time
+type ListOptions struct {
+	Filter ListFilter
+	Sort   SortOrder
+	Limit  int
+	// Cursor is the opaque token returned as 'NextCursor' of the previous page, empty for the first page
+	Cursor string
+}
+
+func (lo *ListOptions) Sanitize() {
+	lo.Filter.Sanitize()
+	lo.Cursor = strings.TrimSpace(lo.Cursor)
+	if lo.Sort == "" {
+		lo.Sort = SortCreatedAtAsc
+	}
+
+	if lo.Limit <= 0 {
+		lo.Limit = DefaultListLimit
+	} else if lo.Limit > MaxListLimit {
+		lo.Limit = MaxListLimit
+	}
+}
+
+func (lo *ListOptions) Validate() error {
+	err := lo.Sort.Validate()
+	if err != nil {
+		return err
+	}
+
+	return lo.Filter.Validate()
+}
+
+// List is a single page of users
+type List struct {
+	Users []User
+	// NextCursor is empty if there are no more pages
+	NextCursor string
+}
+
+// PageCursor has the sort key values of the last user of a page, so the next page can be
+// fetched using keyset pagination. Only the value of the column being sorted on is set, along
+// with the ID which breaks ties
+type PageCursor struct {
+	Sort      SortOrder `json:"s"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	Email     string    `json:"e,omitempty"`
+	ID        string    `json:"i"`
+}
+
+func newPageCursor(sort SortOrder, user *User) *PageCursor {
+	pc := &PageCursor{
+		Sort: sort,
+		ID:   user.ID,
+	}
+
+	switch sort {
+	case SortEmailAsc, SortEmailDesc:
+		pc.Email = user.Email
+	default:
+		pc.CreatedAt = user.CreatedAt
+	}
+
+	return pc
+}
+
+// Value returns the value of the sort column of the cursor
+func (pc *PageCursor) Value() any {
+	switch pc.Sort {
+	case SortEmailAsc, SortEmailDesc:
+		return pc.Email
+	default:
+		return pc.CreatedAt
+	}
+}
+
+func (pc *PageCursor) Encode() string {
+	b, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageCursor(token string, sort SortOrder) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	pc := new(PageCursor)
+	err = json.Unmarshal(b, pc)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	if pc.Sort != sort {
+		return nil, errors.Validation("cursor does not match the sort order")
+	}
+
+	// the ID is validated here, else a crafted cursor would fail in the datastore instead
+	_, err = uuid.Parse(pc.ID)
+	if err != nil {
+		return nil, errors.ValidationErr(err, "invalid cursor")
+	}
+
+	return pc, nil
+}
+
+// ListUsers returns a page of users matching the filters, in the requested sort order
+func (us *Users) ListUsers(ctx context.Context, opts *ListOptions) (*List, error) {
+	if opts == nil {
+		opts = new(ListOptions)
+	}
+
+	opts.Sanitize()
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := decodePageCursor(opts.Cursor, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra is fetched to know if there's a next page
+	list, err := us.store.ListUsers(ctx, opts, after, opts.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &List{
+		Users: list,
+	}
+	if len(list) > opts.Limit {
+		result.Users = list[:opts.Limit]
+		result.NextCursor = newPageCursor(opts.Sort, &result.Users[opts.Limit-1]).Encode()
+	}
+
+	return result, nil
+}
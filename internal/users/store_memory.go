@@ -0,0 +1,329 @@
+package users
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+)
+
+// memstore is an in-memory, thread-safe, implementation of the users store, meant for local
+// development and tests. Emails are unique irrespective of case, same as in Postgres. Though
+// sorting by email is by byte order, while Postgres sorts as per the collation of the DB. So the
+// order of emails differing only in case or with non-ASCII characters could differ
+type memstore struct {
+	mutex sync.RWMutex
+	users map[string]User
+	// emails maps email to the user ID, to enforce unique emails
+	emails map[string]string
+}
+
+// emailKey is the key of an email in emails. Emails are case-insensitive, same as the unique
+// index on lower(email) in Postgres
+func emailKey(email string) string {
+	return strings.ToLower(email)
+}
+
+func (ms *memstore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	userID, ok := ms.emails[emailKey(email)]
+	if !ok {
+		return nil, errors.NotFoundErr(ErrUserEmailNotFound, email)
+	}
+
+	user := ms.users[userID]
+	return &user, nil
+}
+
+func (ms *memstore) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	user, ok := ms.users[userID]
+	if !ok {
+		return nil, errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	return &user, nil
+}
+
+func (ms *memstore) SaveUser(ctx context.Context, user *User) (string, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, ok := ms.emails[emailKey(user.Email)]; ok {
+		return "", errors.DuplicateErr(ErrUserEmailAlreadyExists, user.Email)
+	}
+
+	user.ID = uuid.NewString()
+	ms.insert(*user)
+
+	return user.ID, nil
+}
+
+// BulkSaveUser saves either all the users or none of them
+func (ms *memstore) BulkSaveUser(ctx context.Context, users []User) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	emails := make(map[string]struct{}, len(users))
+	for _, user := range users {
+		_, existing := ms.emails[emailKey(user.Email)]
+		_, inBatch := emails[emailKey(user.Email)]
+		if existing || inBatch {
+			return errors.DuplicateErr(ErrUserEmailAlreadyExists, user.Email)
+		}
+		emails[emailKey(user.Email)] = struct{}{}
+
+		if _, ok := ms.users[user.ID]; ok {
+			return errors.Duplicatef("user with ID %s already exists", user.ID)
+		}
+	}
+
+	for _, user := range users {
+		if user.ID == "" {
+			user.ID = uuid.NewString()
+		}
+		ms.insert(user)
+	}
+
+	return nil
+}
+
+func (ms *memstore) UpdateUser(ctx context.Context, user *User) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	existing, ok := ms.users[user.ID]
+	if !ok {
+		return errors.NotFoundErr(ErrUserNotFound, user.ID)
+	}
+
+	if ownerID, ok := ms.emails[emailKey(user.Email)]; ok && ownerID != user.ID {
+		return errors.DuplicateErr(ErrUserEmailAlreadyExists, user.Email)
+	}
+
+	delete(ms.emails, emailKey(existing.Email))
+	updated := *user
+	if updated.Status == "" {
+		updated.Status = existing.Status
+	}
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = now()
+	ms.users[updated.ID] = updated
+	ms.emails[emailKey(updated.Email)] = updated.ID
+
+	return nil
+}
+
+func (ms *memstore) UpdateUserStatus(ctx context.Context, userID string, email string, status UserStatus) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	user, ok := ms.users[userID]
+	if !ok || emailKey(user.Email) != emailKey(email) {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	user.Status = status
+	user.UpdatedAt = now()
+	ms.users[userID] = user
+
+	return nil
+}
+
+func (ms *memstore) DeleteUser(ctx context.Context, userID string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	user, ok := ms.users[userID]
+	if !ok {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	delete(ms.users, userID)
+	delete(ms.emails, emailKey(user.Email))
+
+	return nil
+}
+
+func (ms *memstore) ListUsers(ctx context.Context, opts *ListOptions, after *PageCursor, limit int) ([]User, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	filter := opts.Filter
+	domain := "@" + strings.ToLower(filter.EmailDomain)
+	namePrefix := strings.ToLower(filter.NamePrefix)
+
+	list := make([]User, 0, limit)
+	for _, user := range ms.users {
+		if filter.EmailDomain != "" && !strings.HasSuffix(strings.ToLower(user.Email), domain) {
+			continue
+		}
+		if filter.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(user.FullName), namePrefix) {
+			continue
+		}
+		if !filter.CreatedAfter.IsZero() && user.CreatedAt.Before(filter.CreatedAfter) {
+			continue
+		}
+		if !filter.CreatedBefore.IsZero() && !user.CreatedAt.Before(filter.CreatedBefore) {
+			continue
+		}
+		if after != nil && !ms.isAfter(opts.Sort, &user, after) {
+			continue
+		}
+		list = append(list, user)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return ms.isAfter(opts.Sort, &list[j], newPageCursor(opts.Sort, &list[i]))
+	})
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+func (ms *memstore) ExportUsers(ctx context.Context, opts *ListOptions, after *PageCursor, each func(*User) error) error {
+	ms.mutex.RLock()
+	total := len(ms.users)
+	ms.mutex.RUnlock()
+
+	list, err := ms.ListUsers(ctx, opts, after, total)
+	if err != nil {
+		return err
+	}
+
+	for idx := range list {
+		err = each(&list[idx])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ms *memstore) UserEmails(ctx context.Context, each func(userID string, email string) error) error {
+	ms.mutex.RLock()
+	accounts := make([][2]string, 0, len(ms.users))
+	for id, user := range ms.users {
+		accounts = append(accounts, [2]string{id, user.Email})
+	}
+	ms.mutex.RUnlock()
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i][0] < accounts[j][0]
+	})
+
+	for _, acc := range accounts {
+		err := each(acc[0], acc[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ms *memstore) UpdateUserEmail(ctx context.Context, userID string, email string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	user, ok := ms.users[userID]
+	if !ok {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	if ownerID, ok := ms.emails[emailKey(email)]; ok && ownerID != userID {
+		return errors.DuplicateErr(ErrUserEmailAlreadyExists, email)
+	}
+
+	delete(ms.emails, emailKey(user.Email))
+	user.Email = email
+	user.UpdatedAt = now()
+	ms.users[userID] = user
+	ms.emails[emailKey(email)] = userID
+
+	return nil
+}
+
+// isAfter returns true if the user comes after the cursor in the given sort order
+func (ms *memstore) isAfter(order SortOrder, user *User, pc *PageCursor) bool {
+	cmp := 0
+	switch order {
+	case SortEmailAsc, SortEmailDesc:
+		cmp = strings.Compare(user.Email, pc.Email)
+	default:
+		cmp = user.CreatedAt.Compare(pc.CreatedAt)
+	}
+
+	if cmp == 0 {
+		cmp = strings.Compare(user.ID, pc.ID)
+	}
+
+	_, desc := order.Column()
+	if desc {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+func (ms *memstore) insert(user User) {
+	user.Status = StatusPending
+	user.CreatedAt = now()
+	user.UpdatedAt = user.CreatedAt
+	ms.users[user.ID] = user
+	ms.emails[emailKey(user.Email)] = user.ID
+}
+
+// now returns the current time truncated to the precision of Postgres' timestamptz
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+func (ms *memstore) BulkSaveUserPartial(ctx context.Context, users []User) ([]RowStatus, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	statuses := make([]RowStatus, len(users))
+	for idx, user := range users {
+		if user.ID == "" {
+			statuses[idx] = RowInvalid
+			continue
+		}
+
+		if existing, ok := ms.users[user.ID]; ok {
+			statuses[idx] = RowDuplicate
+			if existing.Email == user.Email {
+				statuses[idx] = RowInserted
+			}
+			continue
+		}
+
+		if _, ok := ms.emails[emailKey(user.Email)]; ok {
+			statuses[idx] = RowDuplicate
+			continue
+		}
+
+		ms.insert(user)
+		statuses[idx] = RowInserted
+	}
+
+	return statuses, nil
+}
+
+func NewMemoryStore() store {
+	return &memstore{
+		users:  map[string]User{},
+		emails: map[string]string{},
+	}
+}
@@ -3,11 +3,13 @@ package users
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/naughtygopher/errors"
 )
@@ -18,38 +20,80 @@ type pgstore struct {
 	tableName string
 }
 
-func (ps *pgstore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+var userColumns = []string{
+	"id",
+	"full_name",
+	"email",
+	"phone",
+	"contact_address",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+// scanUser scans a row with all the columns in userColumns, in the same order
+func scanUser(row pgx.Row) (*User, error) {
+	user := new(User)
+	uid := new(uuid.NullUUID)
+	address := new(sql.NullString)
+	phone := new(sql.NullString)
+	createdAt := new(sql.NullTime)
+	updatedAt := new(sql.NullTime)
+
+	err := row.Scan(uid, &user.FullName, &user.Email, phone, address, &user.Status, createdAt, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	user.ID = uid.UUID.String()
+	user.ContactAddress = address.String
+	user.Phone = phone.String
+	user.CreatedAt = createdAt.Time
+	user.UpdatedAt = updatedAt.Time
+
+	return user, nil
+}
+
+func (ps *pgstore) getUser(ctx context.Context, where squirrel.Sqlizer) (*User, error) {
 	query, args, err := ps.qbuilder.Select(
-		"id",
-		"full_name",
-		"email",
-		"phone",
-		"contact_address",
+		userColumns...,
 	).From(
 		ps.tableName,
 	).Where(
-		squirrel.Eq{"email": email},
+		where,
 	).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed preparing query")
 	}
 
-	user := new(User)
-	uid := new(uuid.NullUUID)
-	address := new(sql.NullString)
-	phone := new(sql.NullString)
+	return scanUser(ps.pqdriver.QueryRow(ctx, query, args...))
+}
 
-	row := ps.pqdriver.QueryRow(ctx, query, args...)
-	err = row.Scan(uid, &user.FullName, &user.Email, phone, address)
+func (ps *pgstore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	// lower(email) uses the case-insensitive unique index, and also matches the existing users
+	// whose emails are not normalized yet
+	user, err := ps.getUser(ctx, squirrel.Expr("lower(email) = lower(?)", email))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, errors.NotFoundErr(ErrUserEmailNotFound, email)
 		}
 		return nil, errors.Wrap(err, "failed getting user info")
 	}
-	user.ID = uid.UUID.String()
-	user.ContactAddress = address.String
-	user.Phone = phone.String
+
+	return user, nil
+}
+
+func (ps *pgstore) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	if uuid.Validate(userID) != nil {
+		return nil, errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	user, err := ps.getUser(ctx, squirrel.Eq{"id": userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.NotFoundErr(ErrUserNotFound, userID)
+		}
+		return nil, errors.Wrap(err, "failed getting user info")
+	}
 
 	return user, nil
 }
@@ -81,9 +125,10 @@ func (ps *pgstore) SaveUser(ctx context.Context, user *User) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed preparing query")
 	}
+	// status is not inserted, the column's default is StatusPending
 	_, err = ps.pqdriver.Exec(ctx, query, args...)
 	if err != nil {
-		if strings.Contains(err.Error(), "violates unique constraint \"users_email_key\"") {
+		if isEmailUniqueViolation(err) {
 			return "", errors.DuplicateErr(ErrUserEmailAlreadyExists, user.Email)
 		}
 		return "", errors.Wrap(err, "failed storing user info")
@@ -92,32 +137,138 @@ func (ps *pgstore) SaveUser(ctx context.Context, user *User) (string, error) {
 	return user.ID, nil
 }
 
+func (ps *pgstore) UpdateUser(ctx context.Context, user *User) error {
+	fields := map[string]any{
+		"full_name": user.FullName,
+		"email":     user.Email,
+		"phone": sql.NullString{
+			String: user.Phone,
+			Valid:  len(user.Phone) != 0,
+		},
+		"contact_address": sql.NullString{
+			String: user.ContactAddress,
+			Valid:  len(user.ContactAddress) != 0,
+		},
+	}
+	if user.Status != "" {
+		fields["status"] = user.Status
+	}
+
+	query, args, err := ps.qbuilder.Update(
+		ps.tableName,
+	).SetMap(fields).Where(
+		squirrel.Eq{"id": user.ID},
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		if isEmailUniqueViolation(err) {
+			return errors.DuplicateErr(ErrUserEmailAlreadyExists, user.Email)
+		}
+		return errors.Wrap(err, "failed updating user info")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.NotFoundErr(ErrUserNotFound, user.ID)
+	}
+
+	return nil
+}
+
+func (ps *pgstore) UpdateUserStatus(ctx context.Context, userID string, email string, status UserStatus) error {
+	if uuid.Validate(userID) != nil {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	query, args, err := ps.qbuilder.Update(
+		ps.tableName,
+	).Set(
+		"status", status,
+	).Where(
+		squirrel.Eq{"id": userID},
+	).Where(
+		squirrel.Expr("lower(email) = lower(?)", email),
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed updating user status")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	return nil
+}
+
+func (ps *pgstore) DeleteUser(ctx context.Context, userID string) error {
+	if uuid.Validate(userID) != nil {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	query, args, err := ps.qbuilder.Delete(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"id": userID},
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed deleting user")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	return nil
+}
+
+// insertValues returns the values of the user in the same order as insertColumns
+func insertValues(user *User) []any {
+	return []any{
+		user.ID,
+		user.FullName,
+		user.Email,
+		sql.NullString{
+			String: user.Phone,
+			Valid:  len(user.Phone) != 0,
+		},
+		sql.NullString{
+			String: user.ContactAddress,
+			Valid:  len(user.ContactAddress) != 0,
+		},
+	}
+}
+
+var insertColumns = []string{"id", "full_name", "email", "phone", "contact_address"}
+
 func (ps *pgstore) BulkSaveUser(ctx context.Context, users []User) error {
 	rows := make([][]any, 0, len(users))
-
-	for _, user := range users {
-		rows = append(rows, []any{
-			user.ID,
-			user.FullName,
-			user.Email,
-			sql.NullString{
-				String: user.Phone,
-				Valid:  len(user.Phone) != 0,
-			},
-			sql.NullString{
-				String: user.ContactAddress,
-				Valid:  len(user.ContactAddress) != 0,
-			},
-		})
+	for idx := range users {
+		rows = append(rows, insertValues(&users[idx]))
 	}
 
 	inserted, err := ps.pqdriver.CopyFrom(
 		ctx,
 		pgx.Identifier{ps.tableName},
-		[]string{"id", "full_name", "email", "phone", "contact_address"},
+		insertColumns,
 		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
+		if isEmailUniqueViolation(err) {
+			return errors.DuplicateErr(ErrUserEmailAlreadyExists, "one or more users")
+		}
 		return errors.Wrap(err, "failed inserting users")
 	}
 
@@ -133,11 +284,385 @@ func (ps *pgstore) BulkSaveUser(ctx context.Context, users []User) error {
 	return nil
 }
 
+// listQuery returns the query to list users matching the filters in the sort order, after the cursor
+func (ps *pgstore) listQuery(opts *ListOptions, after *PageCursor) squirrel.SelectBuilder {
+	qbuilder := ps.qbuilder.Select(
+		userColumns...,
+	).From(
+		ps.tableName,
+	)
+
+	filter := opts.Filter
+	if filter.EmailDomain != "" {
+		qbuilder = qbuilder.Where(squirrel.ILike{"email": "%@" + escapeLike(filter.EmailDomain)})
+	}
+	if filter.NamePrefix != "" {
+		qbuilder = qbuilder.Where(squirrel.ILike{"full_name": escapeLike(filter.NamePrefix) + "%"})
+	}
+	if !filter.CreatedAfter.IsZero() {
+		qbuilder = qbuilder.Where(squirrel.GtOrEq{"created_at": filter.CreatedAfter})
+	}
+	if !filter.CreatedBefore.IsZero() {
+		qbuilder = qbuilder.Where(squirrel.Lt{"created_at": filter.CreatedBefore})
+	}
+
+	column, desc := opts.Sort.Column()
+	direction, operator := "ASC", ">"
+	if desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if after != nil {
+		// row value comparison, so that rows with the same sort value are paginated by ID
+		qbuilder = qbuilder.Where(
+			fmt.Sprintf("(%s, id) %s (?, ?)", column, operator),
+			after.Value(),
+			after.ID,
+		)
+	}
+
+	return qbuilder.OrderBy(
+		column+" "+direction,
+		"id "+direction,
+	)
+}
+
+func (ps *pgstore) ListUsers(ctx context.Context, opts *ListOptions, after *PageCursor, limit int) ([]User, error) {
+	query, args, err := ps.listQuery(opts, after).Limit(
+		uint64(limit),
+	).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	rows, err := ps.pqdriver.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing users")
+	}
+	defer rows.Close()
+
+	list := make([]User, 0, limit)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading user")
+		}
+		list = append(list, *user)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing users")
+	}
+
+	return list, nil
+}
+
+const (
+	exportCursorName = "users_export"
+	// exportFetchSize is the number of rows fetched from the cursor at a time
+	exportFetchSize = 1000
+)
+
+func (ps *pgstore) ExportUsers(ctx context.Context, opts *ListOptions, after *PageCursor, each func(*User) error) error {
+	query, args, err := ps.listQuery(opts, after).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	// a cursor lives only within a transaction, and repeatable read gives a consistent
+	// snapshot for the whole of the export
+	tx, err := ps.pqdriver.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed starting transaction")
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	_, err = tx.Exec(
+		ctx,
+		fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", exportCursorName, query),
+		args...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed declaring cursor")
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", exportFetchSize, exportCursorName)
+	for {
+		fetched, err := ps.fetchUsers(ctx, tx, fetch, each)
+		if err != nil {
+			return err
+		}
+
+		if fetched < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+func (ps *pgstore) UserEmails(ctx context.Context, each func(userID string, email string) error) error {
+	query, args, err := ps.qbuilder.Select("id", "email").From(ps.tableName).OrderBy("id").ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	rows, err := ps.pqdriver.Query(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed listing user emails")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		uid := new(uuid.NullUUID)
+		email := ""
+		err = rows.Scan(uid, &email)
+		if err != nil {
+			return errors.Wrap(err, "failed reading user email")
+		}
+
+		err = each(uid.UUID.String(), email)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return errors.Wrap(err, "failed listing user emails")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) UpdateUserEmail(ctx context.Context, userID string, email string) error {
+	query, args, err := ps.qbuilder.Update(
+		ps.tableName,
+	).Set(
+		"email", email,
+	).Where(
+		squirrel.Eq{"id": userID},
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	tag, err := ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		if isEmailUniqueViolation(err) {
+			return errors.DuplicateErr(ErrUserEmailAlreadyExists, email)
+		}
+		return errors.Wrap(err, "failed updating user email")
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.NotFoundErr(ErrUserNotFound, userID)
+	}
+
+	return nil
+}
+
+// fetchUsers runs the fetch query on the cursor, calls each for every user fetched
+// and returns the number of users fetched
+func (ps *pgstore) fetchUsers(ctx context.Context, tx pgx.Tx, fetch string, each func(*User) error) (int, error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed fetching users")
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		user, err := scanUser(rows)
+		if err != nil {
+			return fetched, errors.Wrap(err, "failed reading user")
+		}
+
+		err = each(user)
+		if err != nil {
+			return fetched, err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fetched, errors.Wrap(err, "failed fetching users")
+	}
+
+	return fetched, nil
+}
+
+// escapeLike escapes the special characters of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// bulkInsertChunkSize is the number of users inserted per statement, when COPY cannot be used
+const bulkInsertChunkSize = 500
+
+func (ps *pgstore) BulkSaveUserPartial(ctx context.Context, users []User) ([]RowStatus, error) {
+	statuses := make([]RowStatus, len(users))
+	if len(users) == 0 {
+		return statuses, nil
+	}
+
+	// COPY is the fastest, though it fails entirely even if a single row fails
+	err := ps.BulkSaveUser(ctx, users)
+	if err == nil {
+		for idx := range statuses {
+			statuses[idx] = RowInserted
+		}
+		return statuses, nil
+	}
+
+	if !isDataError(err) {
+		return nil, err
+	}
+
+	for start := 0; start < len(users); start += bulkInsertChunkSize {
+		end := min(start+bulkInsertChunkSize, len(users))
+		err = ps.insertChunk(ctx, users[start:end], statuses[start:end])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return statuses, nil
+}
+
+// insertChunk inserts the users with a single INSERT ... ON CONFLICT DO NOTHING, and sets the status
+// of each user. If the chunk fails because of invalid data, users are inserted one at a time
+func (ps *pgstore) insertChunk(ctx context.Context, users []User, statuses []RowStatus) error {
+	qbuilder := ps.qbuilder.Insert(ps.tableName).Columns(insertColumns...)
+	for idx := range users {
+		qbuilder = qbuilder.Values(insertValues(&users[idx])...)
+	}
+
+	query, args, err := qbuilder.Suffix("ON CONFLICT DO NOTHING RETURNING id").ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	var inserted []string
+	rows, err := ps.pqdriver.Query(ctx, query, args...)
+	if err == nil {
+		inserted, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	if err != nil {
+		if !isDataError(err) {
+			return errors.Wrap(err, "failed inserting users")
+		}
+
+		if len(users) == 1 {
+			statuses[0] = RowInvalid
+			return nil
+		}
+
+		for idx := range users {
+			err = ps.insertChunk(ctx, users[idx:idx+1], statuses[idx:idx+1])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	insertedIDs := make(map[string]struct{}, len(inserted))
+	for _, id := range inserted {
+		insertedIDs[id] = struct{}{}
+	}
+
+	conflicts := make([]string, 0, len(users)-len(inserted))
+	for idx := range users {
+		if _, ok := insertedIDs[users[idx].ID]; ok {
+			statuses[idx] = RowInserted
+			continue
+		}
+		statuses[idx] = RowDuplicate
+		conflicts = append(conflicts, users[idx].ID)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return ps.markPreviouslyInserted(ctx, users, statuses, conflicts)
+}
+
+// markPreviouslyInserted marks the conflicting users, which already exist with the same ID & email
+// as inserted. e.g. inserted by a previous attempt of the same batch
+func (ps *pgstore) markPreviouslyInserted(ctx context.Context, users []User, statuses []RowStatus, ids []string) error {
+	query, args, err := ps.qbuilder.Select("id", "email").From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"id": ids},
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	rows, err := ps.pqdriver.Query(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed reading existing users")
+	}
+
+	existing := make(map[string]string, len(ids))
+	for rows.Next() {
+		var id, email string
+		err = rows.Scan(&id, &email)
+		if err != nil {
+			rows.Close()
+			return errors.Wrap(err, "failed reading existing users")
+		}
+		existing[id] = email
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return errors.Wrap(rows.Err(), "failed reading existing users")
+	}
+
+	for idx := range users {
+		if email, ok := existing[users[idx].ID]; ok && statuses[idx] == RowDuplicate && email == users[idx].Email {
+			statuses[idx] = RowInserted
+		}
+	}
+
+	return nil
+}
+
+// isDataError returns true if the error is caused by the data being inserted, e.g. unique
+// violation or invalid value. i.e. retrying would not help
+func isDataError(err error) bool {
+	if errors.Type(err) == errors.TypeDuplicate {
+		return true
+	}
+
+	pgErr := new(pgconn.PgError)
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// class 22: data exception, class 23: integrity constraint violation
+	return strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")
+}
+
+// isEmailUniqueViolation returns true for a violation of either the case-sensitive unique
+// constraint or the case-insensitive unique index (which replaces it) on email
+func isEmailUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "violates unique constraint \"users_email_key\"") ||
+		strings.Contains(err.Error(), "violates unique constraint \"users_email_lower_key\"")
+}
+
 func (ps *pgstore) newUserID() string {
 	return uuid.NewString()
 }
 
-func NewPostgresStore(pqdriver *pgxpool.Pool, tablename string) *pgstore {
+func NewPostgresStore(pqdriver *pgxpool.Pool, tablename string) store {
 	return &pgstore{
 		qbuilder:  squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 		pqdriver:  pqdriver,
@@ -0,0 +1,23 @@
+package users_test
+
+import (
+	"testing"
+
+	"github.com/naughtygopher/goapp/internal/pkg/postgres/postgrestest"
+	"github.com/naughtygopher/goapp/internal/users"
+	"github.com/naughtygopher/goapp/internal/users/userstest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	userstest.RunStoreSuite(t, func(t *testing.T) users.Store {
+		return users.NewMemoryStore()
+	})
+}
+
+// TestPostgresStore runs only if GOAPP_TEST_POSTGRES_DSN is set
+func TestPostgresStore(t *testing.T) {
+	pool := postgrestest.NewPool(t)
+	userstest.RunStoreSuite(t, func(t *testing.T) users.Store {
+		return users.NewPostgresStore(pool, "users")
+	})
+}
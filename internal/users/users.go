@@ -2,15 +2,35 @@ package users
 
 import (
 	"context"
+	"net/mail"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/naughtygopher/errors"
-	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/naughtygopher/goapp/internal/pkg/jobqueue"
 )
 
+// JobKindBulkCreate is the kind of background job which saves users in bulk
+const JobKindBulkCreate = "users.bulk_create"
+
 var (
+	ErrUserNotFound           = errors.New("user not found")
 	ErrUserEmailNotFound      = errors.New("user with the email not found")
 	ErrUserEmailAlreadyExists = errors.New("user with the email already exists")
+	ErrUserNotVerified        = errors.New("user is not verified")
+)
+
+// UserStatus is the verification status of a user's email
+type UserStatus string
+
+const (
+	// StatusPending is the status of a user whose email is not verified yet. All new users,
+	// and users whose email is changed, are pending
+	StatusPending  UserStatus = "pending"
+	StatusVerified UserStatus = "verified"
 )
 
 type User struct {
@@ -19,36 +39,164 @@ type User struct {
 	Email          string
 	Phone          string
 	ContactAddress string
+	Status         UserStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsVerified returns true if the email of the user is verified
+func (us *User) IsVerified() bool {
+	return us.Status == StatusVerified
+}
+
+// maxEmailLength is the maximum length of an email address as per RFC 5321
+const maxEmailLength = 254
+
+// FieldError is the validation error of a single field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors has the validation errors of all the invalid fields
+type FieldErrors []FieldError
+
+func (fe FieldErrors) Error() string {
+	msgs := make([]string, 0, len(fe))
+	for _, ferr := range fe {
+		msgs = append(msgs, ferr.Field+": "+ferr.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// NormalizeEmail returns the email trimmed, lowercased and in the Unicode normal form NFC.
+// So that the same address written differently, e.g. Foo@Example.com & foo@example.com is
+// treated as the same user
+func NormalizeEmail(email string) string {
+	return norm.NFC.String(strings.ToLower(strings.TrimSpace(email)))
+}
+
+func validateEmail(email string) string {
+	if email == "" {
+		return "cannot be empty"
+	}
+
+	if len(email) > maxEmailLength {
+		return "cannot be longer than 254 characters"
+	}
+
+	// the address should be the whole of the input, i.e. no display name like "Name <email>"
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "invalid email address"
+	}
+
+	return ""
 }
 
-// ValidateForCreate runs the validation required for when a user is being created. i.e. ID is not available
+// ValidateForCreate runs the validation required for when a user is being created. i.e. ID is not available.
+// The validation errors of all the fields are returned together as FieldErrors
 func (us *User) ValidateForCreate() error {
+	ferrs := make(FieldErrors, 0, 2)
 	if us.FullName == "" {
-		return errors.Validation("full name cannot be empty")
+		ferrs = append(ferrs, FieldError{Field: "FullName", Message: "cannot be empty"})
 	}
 
-	if us.Email == "" {
-		return errors.Validation("email cannot be empty")
+	if msg := validateEmail(us.Email); msg != "" {
+		ferrs = append(ferrs, FieldError{Field: "Email", Message: msg})
+	}
+
+	if len(ferrs) == 0 {
+		return nil
+	}
+
+	return errors.ValidationErrf(ferrs, "invalid user, %s", ferrs.Error())
+}
+
+// ValidateForUpdate runs the validation required for when an existing user is being updated
+func (us *User) ValidateForUpdate() error {
+	if us.ID == "" {
+		return errors.Validation("user ID cannot be empty")
 	}
 
-	return nil
+	return us.ValidateForCreate()
 }
 
 func (us *User) Sanitize() {
 	us.ID = strings.TrimSpace(us.ID)
+	// IDs provided by clients are canonicalized to the form the datastore returns them in, e.g.
+	// Postgres returns UUIDs in lowercase, so that the IDs returned can be matched with the input
+	id, err := uuid.Parse(us.ID)
+	if err == nil {
+		us.ID = id.String()
+	}
 	us.FullName = strings.TrimSpace(us.FullName)
-	us.Email = strings.TrimSpace(us.Email)
+	us.Email = NormalizeEmail(us.Email)
 	us.Phone = strings.TrimSpace(us.Phone)
 	us.ContactAddress = strings.TrimSpace(us.ContactAddress)
 }
 
+// UserUpdate holds the fields of a user which can be updated. Only the non-nil fields
+// are applied, which allows partial updates
+type UserUpdate struct {
+	FullName       *string
+	Email          *string
+	Phone          *string
+	ContactAddress *string
+}
+
+// Apply overwrites the respective fields of user with all the non-nil fields of the update
+func (uu *UserUpdate) Apply(user *User) {
+	if uu.FullName != nil {
+		user.FullName = *uu.FullName
+	}
+	if uu.Email != nil {
+		user.Email = *uu.Email
+	}
+	if uu.Phone != nil {
+		user.Phone = *uu.Phone
+	}
+	if uu.ContactAddress != nil {
+		user.ContactAddress = *uu.ContactAddress
+	}
+}
+
+// store is the datastore of users. The status of a new user is always StatusPending, irrespective
+// of the status of the user being saved
 type store interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, userID string) (*User, error)
 	SaveUser(ctx context.Context, user *User) (string, error)
 	BulkSaveUser(ctx context.Context, users []User) error
+	// BulkSaveUserPartial saves all the users it can, and returns the status of every user in the
+	// same order. Users should have their IDs set. A user which already exists with the same ID
+	// and email (e.g. saved by a previous attempt) is considered inserted
+	BulkSaveUserPartial(ctx context.Context, users []User) ([]RowStatus, error)
+	// UpdateUser updates the user, the status is not updated if empty
+	UpdateUser(ctx context.Context, user *User) error
+	// UpdateUserStatus updates the status of the user, only if the email of the user is still the
+	// same (case-insensitive). Else ErrUserNotFound is returned
+	UpdateUserStatus(ctx context.Context, userID string, email string, status UserStatus) error
+	DeleteUser(ctx context.Context, userID string) error
+	ListUsers(ctx context.Context, opts *ListOptions, after *PageCursor, limit int) ([]User, error)
+	// ExportUsers calls each for every user matching the filters, in the sort order, without
+	// holding all of them in memory. It stops at the first error returned by each
+	ExportUsers(ctx context.Context, opts *ListOptions, after *PageCursor, each func(*User) error) error
+	// UserEmails calls each for the ID & email of every user. Unlike the other methods, it depends
+	// only on the columns of the initial schema, so it works before the later migrations are applied
+	UserEmails(ctx context.Context, each func(userID string, email string) error) error
+	// UpdateUserEmail updates only the email of the user, and depends only on the initial schema
+	// same as UserEmails
+	UpdateUserEmail(ctx context.Context, userID string, email string) error
 }
+
+// Store is the datastore of users as seen outside the package, userstest.RunStoreSuite verifies
+// an implementation of it
+type Store = store
+
 type Users struct {
 	store store
+	jobs  *jobqueue.Queue
 }
 
 func (us *Users) CreateUser(ctx context.Context, user *User) (*User, error) {
@@ -63,11 +211,13 @@ func (us *Users) CreateUser(ctx context.Context, user *User) (*User, error) {
 		return nil, err
 	}
 	user.ID = newID
+	user.Status = StatusPending
 
 	return user, nil
 }
 
 func (us *Users) ReadByEmail(ctx context.Context, email string) (*User, error) {
+	email = NormalizeEmail(email)
 	if email == "" {
 		return nil, errors.Validation("no email provided")
 	}
@@ -75,32 +225,98 @@ func (us *Users) ReadByEmail(ctx context.Context, email string) (*User, error) {
 	return us.store.GetUserByEmail(ctx, email)
 }
 
-func (us *Users) AsyncCreateUsers(ctx context.Context, users []User) error {
-	errList := make([]error, 0, len(users))
-	for i := range users {
-		err := users[i].ValidateForCreate()
-		if err != nil {
-			errList = append(errList, err)
-		}
+func (us *Users) ReadByID(ctx context.Context, userID string) (*User, error) {
+	if userID == "" {
+		return nil, errors.Validation("no user ID provided")
+	}
+
+	return us.store.GetUserByID(ctx, userID)
+}
+
+// UpdateUser applies the partial update on the existing user, and stores the result after
+// sanitizing and validating it
+func (us *Users) UpdateUser(ctx context.Context, userID string, uu *UserUpdate) (*User, error) {
+	if uu == nil {
+		return nil, errors.Validation("no update provided")
+	}
+
+	user, err := us.ReadByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := user.Email
+	uu.Apply(user)
+	user.Sanitize()
+	err = user.ValidateForUpdate()
+	if err != nil {
+		return nil, err
+	}
+
+	// a new email has to be verified again
+	if user.Email != NormalizeEmail(email) {
+		user.Status = StatusPending
+	}
+
+	err = us.store.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// MarkVerified marks the user as verified, only if the email of the user is still the given email.
+// i.e. the email was not changed after the verification was sent
+func (us *Users) MarkVerified(ctx context.Context, userID string, email string) error {
+	if userID == "" || email == "" {
+		return errors.Validation("user ID and email are required")
+	}
+
+	return us.store.UpdateUserStatus(ctx, userID, email, StatusVerified)
+}
+
+func (us *Users) DeleteUser(ctx context.Context, userID string) error {
+	if userID == "" {
+		return errors.Validation("no user ID provided")
 	}
 
-	if len(errList) != 0 {
-		return errors.Join(errList...)
+	return us.store.DeleteUser(ctx, userID)
+}
+
+// AsyncCreateUsers enqueues a job to save the users in bulk, and returns the ID of the job.
+// Same as BulkCreateUsers, invalid users do not prevent the rest from being saved. They are
+// reported per row in the result of the job
+func (us *Users) AsyncCreateUsers(ctx context.Context, users []User) (string, error) {
+	if us.jobs == nil {
+		return "", errors.New("job queue is not configured")
 	}
 
-	go func() {
-		ctx := context.TODO()
-		err := us.store.BulkSaveUser(context.TODO(), users)
-		if err != nil {
-			logger.Error(ctx, err, users)
+	for i := range users {
+		users[i].Sanitize()
+		// IDs are assigned before enqueuing, so that retries of the job do not create the users again
+		if users[i].ID == "" {
+			users[i].ID = uuid.NewString()
 		}
-	}()
+	}
 
-	return nil
+	jobID, err := us.jobs.Enqueue(ctx, JobKindBulkCreate, users)
+	if err != nil {
+		return "", errors.Wrap(err, "failed enqueuing users")
+	}
+
+	return jobID, nil
 }
 
-func NewService(store store) *Users {
-	return &Users{
+// NewService returns the users service. The job queue is optional, and is required only for
+// async APIs like AsyncCreateUsers
+func NewService(store store, jobs *jobqueue.Queue) *Users {
+	us := &Users{
 		store: store,
+		jobs:  jobs,
+	}
+	if jobs != nil {
+		jobs.Register(JobKindBulkCreate, jobqueue.TypedHandler(us.bulkCreateJob))
 	}
+	return us
 }
@@ -1,8 +1,12 @@
 package users
 
 import (
+	"encoding/base64"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/naughtygopher/errors"
 )
 
 func TestUser_Sanitize(t *testing.T) {
@@ -23,11 +27,20 @@ func TestUser_Sanitize(t *testing.T) {
 			output: User{
 				ID:             "ID",
 				FullName:       "Fullname",
-				Email:          "Email",
+				Email:          "email",
 				Phone:          "Phone",
 				ContactAddress: "Contact Address",
 			},
 		},
+		{
+			name: "canonical UUID",
+			input: User{
+				ID: " 0190B6C4-43D5-7D6B-9D5B-1F3F3C1E2A9B ",
+			},
+			output: User{
+				ID: "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -86,6 +99,22 @@ func TestUser_ValidateForCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid email",
+			fields: fields{
+				FullName: "Full Name",
+				Email:    "name.example.com",
+			},
+			wantErr: true,
+		},
+		{
+			name: "email with display name",
+			fields: fields{
+				FullName: "Full Name",
+				Email:    "Name <name@example.com>",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -102,3 +131,164 @@ func TestUser_ValidateForCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_ValidateForCreate_FieldErrors(t *testing.T) {
+	us := &User{Email: "invalid"}
+	err := us.ValidateForCreate()
+
+	ferrs := FieldErrors{}
+	if !errors.As(err, &ferrs) {
+		t.Fatalf("expected FieldErrors, got %v", err)
+	}
+
+	expected := FieldErrors{
+		{Field: "FullName", Message: "cannot be empty"},
+		{Field: "Email", Message: "invalid email address"},
+	}
+	if !reflect.DeepEqual(ferrs, expected) {
+		t.Errorf("got: %+v, expected: %+v", ferrs, expected)
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{input: " Foo@Example.com ", output: "foo@example.com"},
+		{input: "foo@example.com", output: "foo@example.com"},
+		// decomposed 'e' + combining acute accent is composed into 'é'
+		{input: "Jose\u0301@example.com", output: "jos\u00e9@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeEmail(tt.input); got != tt.output {
+				t.Errorf("got: %q, expected: %q", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestUserUpdate_Apply(t *testing.T) {
+	name := "New Name"
+	phone := ""
+	tests := []struct {
+		name   string
+		update UserUpdate
+		input  User
+		output User
+	}{
+		{
+			name:   "no fields",
+			update: UserUpdate{},
+			input:  User{ID: "ID", FullName: "Name", Email: "name@example.com", Phone: "123"},
+			output: User{ID: "ID", FullName: "Name", Email: "name@example.com", Phone: "123"},
+		},
+		{
+			name:   "partial update",
+			update: UserUpdate{FullName: &name, Phone: &phone},
+			input:  User{ID: "ID", FullName: "Name", Email: "name@example.com", Phone: "123"},
+			output: User{ID: "ID", FullName: "New Name", Email: "name@example.com", Phone: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.update.Apply(&tt.input)
+			if !reflect.DeepEqual(tt.input, tt.output) {
+				t.Errorf("got: %+v, expected: %+v", tt.input, tt.output)
+			}
+		})
+	}
+}
+
+func TestListOptions_Sanitize(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  ListOptions
+		output ListOptions
+	}{
+		{
+			name:  "defaults",
+			input: ListOptions{},
+			output: ListOptions{
+				Sort:  SortCreatedAtAsc,
+				Limit: DefaultListLimit,
+			},
+		},
+		{
+			name: "limit capped and filters trimmed",
+			input: ListOptions{
+				Sort:   SortEmailDesc,
+				Limit:  MaxListLimit + 1,
+				Cursor: " cursor ",
+				Filter: ListFilter{
+					EmailDomain: " @example.com ",
+					NamePrefix:  " Jo ",
+				},
+			},
+			output: ListOptions{
+				Sort:   SortEmailDesc,
+				Limit:  MaxListLimit,
+				Cursor: "cursor",
+				Filter: ListFilter{
+					EmailDomain: "example.com",
+					NamePrefix:  "Jo",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Sanitize()
+			if !reflect.DeepEqual(tt.input, tt.output) {
+				t.Errorf("got: %+v, expected: %+v", tt.input, tt.output)
+			}
+		})
+	}
+}
+
+func TestPageCursor(t *testing.T) {
+	user := &User{
+		ID:        "0190b6c4-43d5-7d6b-9d5b-1f3f3c1e2a9b",
+		Email:     "name@example.com",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+	}
+
+	for _, sort := range []SortOrder{SortCreatedAtAsc, SortCreatedAtDesc, SortEmailAsc, SortEmailDesc} {
+		t.Run(string(sort), func(t *testing.T) {
+			expected := newPageCursor(sort, user)
+			got, err := decodePageCursor(expected.Encode(), sort)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("got: %+v, expected: %+v", got, expected)
+			}
+		})
+	}
+
+	_, err := decodePageCursor(newPageCursor(SortEmailAsc, user).Encode(), SortCreatedAtAsc)
+	if err == nil {
+		t.Error("expected error for cursor with mismatching sort order")
+	}
+
+	_, err = decodePageCursor("not a cursor", SortCreatedAtAsc)
+	if err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+
+	crafted := newPageCursor(SortCreatedAtAsc, &User{ID: "1 OR 1=1", CreatedAt: user.CreatedAt})
+	_, err = decodePageCursor(crafted.Encode(), SortCreatedAtAsc)
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error for cursor with an invalid ID, got %v", err)
+	}
+
+	badTime := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"created_at","c":"yesterday","i":"` + user.ID + `"}`))
+	_, err = decodePageCursor(badTime, SortCreatedAtAsc)
+	if errors.Type(err) != errors.TypeValidation {
+		t.Errorf("expected validation error for cursor with an invalid time, got %v", err)
+	}
+}
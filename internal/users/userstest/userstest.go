@@ -0,0 +1,579 @@
+// Package userstest has the conformance suite which every implementation of the users store
+// must pass, so that all the datastores behave exactly the same
+package userstest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// RunStoreSuite runs the conformance suite against the store returned by newStore. The suite
+// does not expect an empty store, and uses unique emails so it can be run against a shared DB
+func RunStoreSuite(t *testing.T, newStore func(t *testing.T) users.Store) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, st users.Store)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"SaveDuplicateEmail", testSaveDuplicateEmail},
+		{"EmailCaseInsensitive", testEmailCaseInsensitive},
+		{"GetNotFound", testGetNotFound},
+		{"Update", testUpdate},
+		{"UpdateStatus", testUpdateStatus},
+		{"Delete", testDelete},
+		{"BulkSave", testBulkSave},
+		{"BulkSaveAllOrNothing", testBulkSaveAllOrNothing},
+		{"BulkSavePartial", testBulkSavePartial},
+		{"List", testList},
+		{"Export", testExport},
+		{"UserEmails", testUserEmails},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+// newUser returns a valid user with a unique email in the given domain
+func newUser(domain string) *users.User {
+	id := uuid.NewString()
+	return &users.User{
+		FullName:       "Name " + id,
+		Email:          fmt.Sprintf("%s@%s", id, domain),
+		Phone:          "+91 1234567890",
+		ContactAddress: "Addr line 1, line 2, City, PIN, Country",
+	}
+}
+
+// uniqueDomain returns an email domain unique to the test, so that listing can be scoped to it
+func uniqueDomain() string {
+	return uuid.NewString() + ".example.com"
+}
+
+func assertType(t *testing.T, err error, etype int, name string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected %s error, got nil", name)
+	}
+	if errors.TypeInt(err) != etype {
+		t.Fatalf("expected %s error, got: %+v", name, err)
+	}
+}
+
+func assertSameUser(t *testing.T, got *users.User, expected *users.User) {
+	t.Helper()
+	if got.ID != expected.ID ||
+		got.FullName != expected.FullName ||
+		got.Email != expected.Email ||
+		got.Phone != expected.Phone ||
+		got.ContactAddress != expected.ContactAddress {
+		t.Fatalf("got: %+v, expected: %+v", got, expected)
+	}
+}
+
+func testSaveAndGet(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	user := newUser(uniqueDomain())
+
+	id, err := st.SaveUser(ctx, user)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+	if id == "" || id != user.ID {
+		t.Fatalf("SaveUser returned ID '%s', user has ID '%s'", id, user.ID)
+	}
+
+	byID, err := st.GetUserByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetUserByID: %+v", err)
+	}
+	assertSameUser(t, byID, user)
+	if byID.CreatedAt.IsZero() || byID.UpdatedAt.IsZero() {
+		t.Fatalf("expected created & updated time to be set, got: %+v", byID)
+	}
+
+	byEmail, err := st.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %+v", err)
+	}
+	assertSameUser(t, byEmail, user)
+}
+
+func testSaveDuplicateEmail(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	user := newUser(uniqueDomain())
+	_, err := st.SaveUser(ctx, user)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+
+	dup := newUser(uniqueDomain())
+	dup.Email = user.Email
+	_, err = st.SaveUser(ctx, dup)
+	assertType(t, err, errors.TypeDuplicate.Int(), "duplicate")
+}
+
+func testEmailCaseInsensitive(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	svc := users.NewService(st, nil)
+	user := newUser(uniqueDomain())
+	email := user.Email
+	user.Email = " " + strings.ToUpper(email) + " "
+
+	created, err := svc.CreateUser(ctx, user)
+	if err != nil {
+		t.Fatalf("CreateUser: %+v", err)
+	}
+	if created.Email != email {
+		t.Fatalf("expected the email to be normalized to %s, got %s", email, created.Email)
+	}
+
+	got, err := svc.ReadByEmail(ctx, strings.ToUpper(email))
+	if err != nil {
+		t.Fatalf("ReadByEmail: %+v", err)
+	}
+	if got.ID != created.ID {
+		t.Fatalf("expected user %s, got %s", created.ID, got.ID)
+	}
+
+	// the store itself should not allow emails differing only in case
+	dup := newUserWithEmail(strings.ToUpper(email))
+	_, err = st.SaveUser(ctx, dup)
+	assertType(t, err, errors.TypeDuplicate.Int(), "duplicate")
+}
+
+func testGetNotFound(t *testing.T, st users.Store) {
+	ctx := context.Background()
+
+	_, err := st.GetUserByID(ctx, uuid.NewString())
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	_, err = st.GetUserByID(ctx, "not-a-uuid")
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	_, err = st.GetUserByEmail(ctx, newUser(uniqueDomain()).Email)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testUpdate(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	domain := uniqueDomain()
+	user := newUser(domain)
+	other := newUser(domain)
+	for _, u := range []*users.User{user, other} {
+		_, err := st.SaveUser(ctx, u)
+		if err != nil {
+			t.Fatalf("SaveUser: %+v", err)
+		}
+	}
+
+	user.FullName = "Updated Name"
+	user.Phone = ""
+	err := st.UpdateUser(ctx, user)
+	if err != nil {
+		t.Fatalf("UpdateUser: %+v", err)
+	}
+
+	got, err := st.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %+v", err)
+	}
+	assertSameUser(t, got, user)
+
+	user.Email = other.Email
+	err = st.UpdateUser(ctx, user)
+	assertType(t, err, errors.TypeDuplicate.Int(), "duplicate")
+
+	missing := newUser(domain)
+	missing.ID = uuid.NewString()
+	err = st.UpdateUser(ctx, missing)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testUpdateStatus(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	user := newUser(uniqueDomain())
+	// the status of a new user is always pending
+	user.Status = users.StatusVerified
+	_, err := st.SaveUser(ctx, user)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+
+	assertStatus := func(expected users.UserStatus) {
+		t.Helper()
+		got, err := st.GetUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %+v", err)
+		}
+		if got.Status != expected {
+			t.Fatalf("expected status %s, got %s", expected, got.Status)
+		}
+	}
+	assertStatus(users.StatusPending)
+
+	err = st.UpdateUserStatus(ctx, user.ID, "other@"+uniqueDomain(), users.StatusVerified)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+	assertStatus(users.StatusPending)
+
+	err = st.UpdateUserStatus(ctx, user.ID, strings.ToUpper(user.Email), users.StatusVerified)
+	if err != nil {
+		t.Fatalf("UpdateUserStatus: %+v", err)
+	}
+	assertStatus(users.StatusVerified)
+
+	// an empty status is not updated
+	user.Status = ""
+	user.FullName = "Updated Name"
+	err = st.UpdateUser(ctx, user)
+	if err != nil {
+		t.Fatalf("UpdateUser: %+v", err)
+	}
+	assertStatus(users.StatusVerified)
+
+	err = st.UpdateUserStatus(ctx, uuid.NewString(), user.Email, users.StatusVerified)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
+
+func testDelete(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	user := newUser(uniqueDomain())
+	_, err := st.SaveUser(ctx, user)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+
+	err = st.DeleteUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("DeleteUser: %+v", err)
+	}
+
+	_, err = st.GetUserByID(ctx, user.ID)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	err = st.DeleteUser(ctx, user.ID)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+
+	// the email should be available again after deletion
+	_, err = st.SaveUser(ctx, newUserWithEmail(user.Email))
+	if err != nil {
+		t.Fatalf("SaveUser after delete: %+v", err)
+	}
+}
+
+func newUserWithEmail(email string) *users.User {
+	user := newUser("")
+	user.Email = email
+	return user
+}
+
+func newBulkUsers(domain string, count int) []users.User {
+	list := make([]users.User, 0, count)
+	for range count {
+		user := newUser(domain)
+		user.ID = uuid.NewString()
+		list = append(list, *user)
+	}
+	return list
+}
+
+func testBulkSave(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	list := newBulkUsers(uniqueDomain(), 3)
+
+	err := st.BulkSaveUser(ctx, list)
+	if err != nil {
+		t.Fatalf("BulkSaveUser: %+v", err)
+	}
+
+	for i := range list {
+		got, err := st.GetUserByEmail(ctx, list[i].Email)
+		if err != nil {
+			t.Fatalf("GetUserByEmail: %+v", err)
+		}
+		assertSameUser(t, got, &list[i])
+	}
+}
+
+func testBulkSaveAllOrNothing(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	existing := newUser(uniqueDomain())
+	_, err := st.SaveUser(ctx, existing)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+
+	list := newBulkUsers(uniqueDomain(), 3)
+	list[1].Email = existing.Email
+	err = st.BulkSaveUser(ctx, list)
+	if err == nil {
+		t.Fatal("expected error when a user in the batch has a duplicate email")
+	}
+
+	for _, idx := range []int{0, 2} {
+		_, err = st.GetUserByEmail(ctx, list[idx].Email)
+		assertType(t, err, errors.TypeNotFound.Int(), "not found")
+	}
+}
+
+func testBulkSavePartial(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	existing := newUser(uniqueDomain())
+	_, err := st.SaveUser(ctx, existing)
+	if err != nil {
+		t.Fatalf("SaveUser: %+v", err)
+	}
+
+	domain := uniqueDomain()
+	batch := make([]users.User, 0, 5)
+	for range 5 {
+		user := newUser(domain)
+		user.ID = uuid.NewString()
+		batch = append(batch, *user)
+	}
+	// duplicate of an existing user
+	batch[1].Email = existing.Email
+	// duplicate within the batch
+	batch[3].Email = batch[2].Email
+
+	expected := []users.RowStatus{
+		users.RowInserted,
+		users.RowDuplicate,
+		users.RowInserted,
+		users.RowDuplicate,
+		users.RowInserted,
+	}
+
+	// saving the same batch again should have the same result, as the users inserted
+	// by the first attempt are identified by their IDs
+	for attempt := 1; attempt <= 2; attempt++ {
+		statuses, err := st.BulkSaveUserPartial(ctx, batch)
+		if err != nil {
+			t.Fatalf("attempt %d, BulkSaveUserPartial: %+v", attempt, err)
+		}
+
+		if len(statuses) != len(expected) {
+			t.Fatalf("attempt %d, expected %d statuses, got %d", attempt, len(expected), len(statuses))
+		}
+		for idx := range expected {
+			if statuses[idx] != expected[idx] {
+				t.Errorf("attempt %d, row %d: expected %s, got %s", attempt, idx, expected[idx], statuses[idx])
+			}
+		}
+	}
+
+	for _, idx := range []int{0, 2, 4} {
+		got, err := st.GetUserByID(ctx, batch[idx].ID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %+v", err)
+		}
+		if got.Email != batch[idx].Email {
+			t.Errorf("expected email %s, got %s", batch[idx].Email, got.Email)
+		}
+	}
+
+	svc := users.NewService(st, nil)
+	invalid := newUser(domain)
+	invalid.FullName = ""
+	results, err := svc.BulkCreateUsers(ctx, []users.User{*newUser(domain), *invalid})
+	if err != nil {
+		t.Fatalf("BulkCreateUsers: %+v", err)
+	}
+	if results[0].Status != users.RowInserted || results[0].ID == "" {
+		t.Errorf("expected row 0 to be inserted with an ID, got %+v", results[0])
+	}
+	if results[1].Status != users.RowInvalid || results[1].Error == "" {
+		t.Errorf("expected row 1 to be invalid with an error, got %+v", results[1])
+	}
+}
+
+func testList(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	svc := users.NewService(st, nil)
+	domain := uniqueDomain()
+
+	created := make(map[string]struct{})
+	for range 5 {
+		user, err := svc.CreateUser(ctx, newUser(domain))
+		if err != nil {
+			t.Fatalf("CreateUser: %+v", err)
+		}
+		created[user.ID] = struct{}{}
+	}
+
+	for _, order := range []users.SortOrder{
+		users.SortCreatedAtAsc,
+		users.SortCreatedAtDesc,
+		users.SortEmailAsc,
+		users.SortEmailDesc,
+	} {
+		t.Run(string(order), func(t *testing.T) {
+			opts := &users.ListOptions{
+				Filter: users.ListFilter{EmailDomain: domain},
+				Sort:   order,
+				Limit:  2,
+			}
+
+			listed := make([]users.User, 0, len(created))
+			for page := 0; ; page++ {
+				if page > len(created) {
+					t.Fatal("pagination did not end")
+				}
+
+				list, err := svc.ListUsers(ctx, opts)
+				if err != nil {
+					t.Fatalf("ListUsers: %+v", err)
+				}
+				listed = append(listed, list.Users...)
+				if list.NextCursor == "" {
+					break
+				}
+				opts.Cursor = list.NextCursor
+			}
+
+			if len(listed) != len(created) {
+				t.Fatalf("expected %d users, got %d", len(created), len(listed))
+			}
+
+			seen := make(map[string]struct{}, len(listed))
+			for i := range listed {
+				if _, ok := created[listed[i].ID]; !ok {
+					t.Fatalf("unexpected user listed: %+v", listed[i])
+				}
+				if _, ok := seen[listed[i].ID]; ok {
+					t.Fatalf("user listed more than once: %+v", listed[i])
+				}
+				seen[listed[i].ID] = struct{}{}
+
+				if i > 0 && !inOrder(order, &listed[i-1], &listed[i]) {
+					t.Fatalf("users not in %s order: %+v, %+v", order, listed[i-1], listed[i])
+				}
+			}
+		})
+	}
+
+	list, err := svc.ListUsers(ctx, &users.ListOptions{
+		Filter: users.ListFilter{EmailDomain: domain, NamePrefix: "no such name"},
+	})
+	if err != nil {
+		t.Fatalf("ListUsers: %+v", err)
+	}
+	if len(list.Users) != 0 {
+		t.Fatalf("expected no users, got %d", len(list.Users))
+	}
+}
+
+func testExport(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	svc := users.NewService(st, nil)
+	domain := uniqueDomain()
+
+	created := make(map[string]struct{})
+	for range 5 {
+		user, err := svc.CreateUser(ctx, newUser(domain))
+		if err != nil {
+			t.Fatalf("CreateUser: %+v", err)
+		}
+		created[user.ID] = struct{}{}
+	}
+
+	exported := make([]users.User, 0, len(created))
+	err := svc.ExportUsers(ctx, &users.ListOptions{
+		Filter: users.ListFilter{EmailDomain: domain},
+		Sort:   users.SortEmailDesc,
+	}, func(user *users.User) error {
+		exported = append(exported, *user)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportUsers: %+v", err)
+	}
+
+	if len(exported) != len(created) {
+		t.Fatalf("expected %d users, got %d", len(created), len(exported))
+	}
+	for i := range exported {
+		if _, ok := created[exported[i].ID]; !ok {
+			t.Fatalf("unexpected user exported: %+v", exported[i])
+		}
+		if i > 0 && !inOrder(users.SortEmailDesc, &exported[i-1], &exported[i]) {
+			t.Fatalf("users not in order: %+v, %+v", exported[i-1], exported[i])
+		}
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+	err = svc.ExportUsers(ctx, &users.ListOptions{
+		Filter: users.ListFilter{EmailDomain: domain},
+	}, func(user *users.User) error {
+		count++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the error returned by each, got %+v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected the export to stop after 1 user, got %d", count)
+	}
+}
+
+func inOrder(order users.SortOrder, a, b *users.User) bool {
+	switch order {
+	case users.SortCreatedAtAsc:
+		return !a.CreatedAt.After(b.CreatedAt)
+	case users.SortCreatedAtDesc:
+		return !a.CreatedAt.Before(b.CreatedAt)
+	case users.SortEmailAsc:
+		return a.Email <= b.Email
+	case users.SortEmailDesc:
+		return a.Email >= b.Email
+	}
+	return false
+}
+
+func testUserEmails(t *testing.T, st users.Store) {
+	ctx := context.Background()
+	domain := uniqueDomain()
+	user, other := newUser(domain), newUser(domain)
+	for _, u := range []*users.User{user, other} {
+		_, err := st.SaveUser(ctx, u)
+		if err != nil {
+			t.Fatalf("SaveUser: %+v", err)
+		}
+	}
+
+	email := "updated." + user.Email
+	err := st.UpdateUserEmail(ctx, user.ID, email)
+	if err != nil {
+		t.Fatalf("UpdateUserEmail: %+v", err)
+	}
+
+	emails := map[string]string{}
+	err = st.UserEmails(ctx, func(userID string, email string) error {
+		emails[userID] = email
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UserEmails: %+v", err)
+	}
+	if emails[user.ID] != email || emails[other.ID] != other.Email {
+		t.Fatalf("expected emails %s & %s, got %s & %s", email, other.Email, emails[user.ID], emails[other.ID])
+	}
+
+	err = st.UpdateUserEmail(ctx, other.ID, email)
+	assertType(t, err, errors.TypeDuplicate.Int(), "duplicate")
+
+	err = st.UpdateUserEmail(ctx, uuid.NewString(), "missing@"+domain)
+	assertType(t, err, errors.TypeNotFound.Int(), "not found")
+}
@@ -0,0 +1,56 @@
+package verification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/naughtygopher/errors"
+)
+
+// memstore is an in-memory, thread-safe, implementation of the store. It is meant for local
+// development and tests
+type memstore struct {
+	mutex  sync.Mutex
+	tokens map[string]Token
+}
+
+func (ms *memstore) SaveToken(ctx context.Context, token *Token) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.tokens[token.Hash] = *token
+	return nil
+}
+
+func (ms *memstore) GetToken(ctx context.Context, hash string) (*Token, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	token, ok := ms.tokens[hash]
+	if !ok {
+		return nil, errors.NotFoundErr(ErrTokenNotFound, hash)
+	}
+
+	return &token, nil
+}
+
+func (ms *memstore) DeleteUserTokens(ctx context.Context, userID string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := time.Now()
+	for hash, token := range ms.tokens {
+		if token.UserID == userID || !now.Before(token.ExpiresAt) {
+			delete(ms.tokens, hash)
+		}
+	}
+
+	return nil
+}
+
+func NewMemoryStore() store {
+	return &memstore{
+		tokens: map[string]Token{},
+	}
+}
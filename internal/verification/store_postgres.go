@@ -0,0 +1,103 @@
+package verification
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/naughtygopher/errors"
+)
+
+type pgstore struct {
+	qbuilder  squirrel.StatementBuilderType
+	pqdriver  *pgxpool.Pool
+	tableName string
+}
+
+func (ps *pgstore) SaveToken(ctx context.Context, token *Token) error {
+	query, args, err := ps.qbuilder.Insert(
+		ps.tableName,
+	).Columns(
+		"token_hash",
+		"user_id",
+		"email",
+		"expires_at",
+	).Values(
+		token.Hash,
+		token.UserID,
+		token.Email,
+		token.ExpiresAt,
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	_, err = ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed storing verification token")
+	}
+
+	return nil
+}
+
+func (ps *pgstore) GetToken(ctx context.Context, hash string) (*Token, error) {
+	query, args, err := ps.qbuilder.Select(
+		"token_hash",
+		"user_id",
+		"email",
+		"expires_at",
+	).From(
+		ps.tableName,
+	).Where(
+		squirrel.Eq{"token_hash": hash},
+	).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing query")
+	}
+
+	token := new(Token)
+	err = ps.pqdriver.QueryRow(ctx, query, args...).Scan(
+		&token.Hash,
+		&token.UserID,
+		&token.Email,
+		&token.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.NotFoundErr(ErrTokenNotFound, hash)
+		}
+		return nil, errors.Wrap(err, "failed getting verification token")
+	}
+
+	return token, nil
+}
+
+func (ps *pgstore) DeleteUserTokens(ctx context.Context, userID string) error {
+	query, args, err := ps.qbuilder.Delete(
+		ps.tableName,
+	).Where(
+		squirrel.Or{
+			squirrel.Eq{"user_id": userID},
+			squirrel.Expr("expires_at <= now()"),
+		},
+	).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed preparing query")
+	}
+
+	_, err = ps.pqdriver.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed deleting verification tokens")
+	}
+
+	return nil
+}
+
+func NewPostgresStore(pqdriver *pgxpool.Pool, tableName string) store {
+	return &pgstore{
+		qbuilder:  squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		pqdriver:  pqdriver,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,237 @@
+// Package verification verifies the emails of users. A signed token, which expires, is emailed to
+// the user as a link. Only the hash of a token is stored, so that the tokens cannot be used even if
+// the datastore is compromised
+package verification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/pkg/mailer"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+var (
+	ErrTokenInvalid    = errors.New("invalid or expired verification token")
+	ErrTokenNotFound   = errors.New("verification token not found")
+	ErrAlreadyVerified = errors.New("user is already verified")
+)
+
+// Token is a verification token sent to a user, as stored
+type Token struct {
+	// Hash is the SHA-256 of the token
+	Hash   string
+	UserID string
+	// Email is the email the token was sent to
+	Email     string
+	ExpiresAt time.Time
+}
+
+type store interface {
+	SaveToken(ctx context.Context, token *Token) error
+	// GetToken returns the token with the hash. ErrTokenNotFound is returned if there's no such token
+	GetToken(ctx context.Context, hash string) (*Token, error)
+	// DeleteUserTokens deletes all the tokens of the user, and all the expired tokens
+	DeleteUserTokens(ctx context.Context, userID string) error
+}
+
+// userService has the methods of the users service required for verification
+type userService interface {
+	ReadByID(ctx context.Context, userID string) (*users.User, error)
+	MarkVerified(ctx context.Context, userID string, email string) error
+}
+
+type Options struct {
+	// Secret is the key used to sign the tokens
+	Secret string
+	// TokenTTL is the duration for which a token is valid
+	TokenTTL time.Duration
+	// BaseURL is the URL of the app, used for the verification link. e.g. https://example.com
+	BaseURL string
+}
+
+func (opts *Options) withDefaults() {
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = time.Hour * 24
+	}
+}
+
+type Verification struct {
+	store  store
+	users  userService
+	mailer mailer.Mailer
+	opts   Options
+}
+
+// Send emails a new verification link to the user. The links sent earlier remain valid until they
+// expire, and it returns the time at which the new link expires
+func (vr *Verification) Send(ctx context.Context, userID string) (time.Time, error) {
+	if userID == "" {
+		return time.Time{}, errors.Validation("no user ID provided")
+	}
+
+	user, err := vr.users.ReadByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if user.IsVerified() {
+		return time.Time{}, errors.DuplicateErr(ErrAlreadyVerified, userID)
+	}
+
+	expiresAt := time.Now().Add(vr.opts.TokenTTL).Truncate(time.Second)
+	token, err := vr.newToken(user.ID, expiresAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = vr.store.SaveToken(ctx, &Token{
+		Hash:      hashToken(token),
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = vr.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease verify your email by opening the link below, it expires at %s.\n\n%s\n",
+			user.FullName,
+			expiresAt.UTC().Format(time.RFC1123),
+			vr.link(token),
+		),
+	})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed sending verification email")
+	}
+
+	return expiresAt, nil
+}
+
+// Verify marks the user of the token as verified, and invalidates all the tokens of the user.
+// It returns the ID of the user verified
+func (vr *Verification) Verify(ctx context.Context, token string) (string, error) {
+	userID, err := vr.parseToken(token, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	stored, err := vr.store.GetToken(ctx, hashToken(token))
+	if errors.Is(err, ErrTokenNotFound) {
+		return "", errors.ValidationErr(ErrTokenInvalid, "token was already used")
+	} else if err != nil {
+		return "", err
+	}
+
+	if stored.UserID != userID {
+		return "", errors.ValidationErr(ErrTokenInvalid, "token does not belong to the user")
+	}
+
+	// the token is invalid if the email was changed after it was sent. The user is marked verified
+	// before the tokens are deleted, so that a failure in between can be retried with the same token.
+	// Marking verified is idempotent, hence the token being used concurrently is harmless
+	err = vr.users.MarkVerified(ctx, stored.UserID, stored.Email)
+	if errors.Is(err, users.ErrUserNotFound) {
+		return "", errors.ValidationErr(ErrTokenInvalid, "email was changed after the token was sent")
+	} else if err != nil {
+		return "", err
+	}
+
+	err = vr.store.DeleteUserTokens(ctx, stored.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	return stored.UserID, nil
+}
+
+func (vr *Verification) link(token string) string {
+	return strings.TrimSuffix(vr.opts.BaseURL, "/") + "/verify?token=" + url.QueryEscape(token)
+}
+
+// newToken returns a token of the form <payload>.<signature>, where payload is the base64 of
+// "<user ID>:<expiry unix timestamp>:<random nonce>" and signature is the base64 of its HMAC-SHA256
+func (vr *Verification) newToken(userID string, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", errors.Wrap(err, "failed generating token")
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(
+		nil,
+		"%s:%d:%s",
+		userID,
+		expiresAt.Unix(),
+		hex.EncodeToString(nonce),
+	))
+
+	return payload + "." + vr.sign(payload), nil
+}
+
+// parseToken verifies the signature & expiry of the token, and returns the user ID in the token
+func (vr *Verification) parseToken(token string, now time.Time) (string, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || subtle.ConstantTimeCompare([]byte(signature), []byte(vr.sign(payload))) != 1 {
+		return "", errors.ValidationErr(ErrTokenInvalid, "invalid signature")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", errors.ValidationErr(ErrTokenInvalid, "malformed token")
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return "", errors.ValidationErr(ErrTokenInvalid, "malformed token")
+	}
+
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", errors.ValidationErr(ErrTokenInvalid, "malformed token")
+	}
+
+	if !now.Before(time.Unix(expiry, 0)) {
+		return "", errors.ValidationErr(ErrTokenInvalid, "token has expired")
+	}
+
+	return parts[0], nil
+}
+
+func (vr *Verification) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(vr.opts.Secret))
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashToken returns the hash of the token as stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewService(store store, us userService, ml mailer.Mailer, opts Options) *Verification {
+	opts.withDefaults()
+	return &Verification{
+		store:  store,
+		users:  us,
+		mailer: ml,
+		opts:   opts,
+	}
+}
@@ -0,0 +1,228 @@
+package verification
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/pkg/mailer"
+	"github.com/naughtygopher/goapp/internal/users"
+)
+
+// outbox is a mailer which keeps all the messages sent
+type outbox struct {
+	mutex    sync.Mutex
+	messages []mailer.Message
+}
+
+func (ob *outbox) Send(ctx context.Context, msg *mailer.Message) error {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.messages = append(ob.messages, *msg)
+	return nil
+}
+
+// lastToken returns the token in the link of the last message sent
+func (ob *outbox) lastToken(t *testing.T) string {
+	t.Helper()
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+
+	if len(ob.messages) == 0 {
+		t.Fatal("no message sent")
+	}
+
+	body := ob.messages[len(ob.messages)-1].Body
+	_, link, ok := strings.Cut(body, "https://goapp.test/verify?")
+	if !ok {
+		t.Fatalf("no verification link in %q", body)
+	}
+
+	query, err := url.ParseQuery(strings.Fields(link)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query.Get("token")
+}
+
+func setup(t *testing.T) (*Verification, *users.Users, *outbox, *users.User) {
+	t.Helper()
+	us := users.NewService(users.NewMemoryStore(), nil)
+	ob := &outbox{}
+	vr := NewService(NewMemoryStore(), us, ob, Options{
+		Secret:  "test-secret",
+		BaseURL: "https://goapp.test/",
+	})
+
+	user, err := us.CreateUser(context.Background(), &users.User{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return vr, us, ob, user
+}
+
+func assertInvalid(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, ErrTokenInvalid) || errors.Type(err) != errors.TypeValidation {
+		t.Fatalf("expected a validation error for invalid token, got %+v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	vr, us, ob, user := setup(t)
+	if user.IsVerified() {
+		t.Fatal("expected a new user to be pending")
+	}
+
+	_, err := vr.Send(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ob.messages[0].To != user.Email {
+		t.Fatalf("expected the email to be sent to %s, got %s", user.Email, ob.messages[0].To)
+	}
+
+	userID, err := vr.Verify(ctx, ob.lastToken(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != user.ID {
+		t.Fatalf("expected user %s to be verified, got %s", user.ID, userID)
+	}
+
+	verified, err := us.ReadByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.IsVerified() {
+		t.Fatalf("expected the user to be verified, got %s", verified.Status)
+	}
+
+	_, err = vr.Verify(ctx, ob.lastToken(t))
+	assertInvalid(t, err)
+
+	_, err = vr.Send(ctx, user.ID)
+	if errors.Type(err) != errors.TypeDuplicate {
+		t.Fatalf("expected a duplicate error for a verified user, got %+v", err)
+	}
+}
+
+func TestVerify_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+	vr, _, ob, user := setup(t)
+
+	_, err := vr.Send(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := ob.lastToken(t)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	for name, invalid := range map[string]string{
+		"empty":          "",
+		"no signature":   payload,
+		"bad signature":  payload + "." + strings.Repeat("A", len(signature)),
+		"other secret":   payload + "." + (&Verification{opts: Options{Secret: "other"}}).sign(payload),
+		"signed garbage": "garbage." + vr.sign("garbage"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := vr.Verify(ctx, invalid)
+			assertInvalid(t, err)
+		})
+	}
+
+	expired, err := vr.newToken(user.ID, time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vr.Verify(ctx, expired)
+	assertInvalid(t, err)
+
+	// the valid token is still usable after all the invalid attempts
+	_, err = vr.Verify(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify_EmailChanged(t *testing.T) {
+	ctx := context.Background()
+	vr, us, ob, user := setup(t)
+
+	_, err := vr.Send(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := "jane.doe@example.com"
+	_, err = us.UpdateUser(ctx, user.ID, &users.UserUpdate{Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = vr.Verify(ctx, ob.lastToken(t))
+	assertInvalid(t, err)
+
+	updated, err := us.ReadByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.IsVerified() {
+		t.Fatal("expected the user to be pending after the email was changed")
+	}
+}
+
+// flakyUsers fails marking the user verified, until fail is unset
+type flakyUsers struct {
+	*users.Users
+	fail bool
+}
+
+func (fu *flakyUsers) MarkVerified(ctx context.Context, userID string, email string) error {
+	if fu.fail {
+		return errors.New("connection reset")
+	}
+	return fu.Users.MarkVerified(ctx, userID, email)
+}
+
+func TestVerify_Retry(t *testing.T) {
+	ctx := context.Background()
+	vr, us, ob, user := setup(t)
+	flaky := &flakyUsers{Users: us, fail: true}
+	vr.users = flaky
+
+	_, err := vr.Send(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = vr.Verify(ctx, ob.lastToken(t))
+	if err == nil {
+		t.Fatal("expected verification to fail when marking the user verified fails")
+	}
+
+	// the token is not consumed by the failed attempt, so it can be retried
+	flaky.fail = false
+	_, err = vr.Verify(ctx, ob.lastToken(t))
+	if err != nil {
+		t.Fatalf("expected the retry to verify the user, got %+v", err)
+	}
+
+	verified, err := us.ReadByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.IsVerified() {
+		t.Fatalf("expected the user to be verified, got %s", verified.Status)
+	}
+}
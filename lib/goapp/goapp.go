@@ -6,7 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/naughtygopher/errors"
 )
@@ -17,6 +21,132 @@ type User struct {
 	Email          string
 	Phone          string
 	ContactAddress string
+	// Status is the verification status of the email, 'pending' or 'verified'
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserFilter has the filters and sort order used for listing users, zero values are ignored
+type UserFilter struct {
+	EmailDomain   string
+	NamePrefix    string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Sort is one of created_at, -created_at, email, -email
+	Sort string
+	// PageSize is the number of users fetched per request
+	PageSize int
+}
+
+func (uf *UserFilter) query() url.Values {
+	query := url.Values{}
+	if uf == nil {
+		return query
+	}
+
+	if uf.EmailDomain != "" {
+		query.Set("email_domain", uf.EmailDomain)
+	}
+	if uf.NamePrefix != "" {
+		query.Set("name_prefix", uf.NamePrefix)
+	}
+	if !uf.CreatedAfter.IsZero() {
+		query.Set("created_after", uf.CreatedAfter.Format(time.RFC3339))
+	}
+	if !uf.CreatedBefore.IsZero() {
+		query.Set("created_before", uf.CreatedBefore.Format(time.RFC3339))
+	}
+	if uf.Sort != "" {
+		query.Set("sort", uf.Sort)
+	}
+	if uf.PageSize > 0 {
+		query.Set("limit", strconv.Itoa(uf.PageSize))
+	}
+
+	return query
+}
+
+// UserList is a single page of users
+type UserList struct {
+	Users      []User
+	NextCursor string
+}
+
+// Job is a background job, e.g. created when users are created asynchronously
+type Job struct {
+	ID   string
+	Kind string
+	// Status is one of queued, running, succeeded, dead
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+	// Result is available only after the job has succeeded, and is specific to the kind of job
+	Result    json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BulkCreateResult returns the result of a job which creates users in bulk
+func (jb *Job) BulkCreateResult() (*BulkCreateResult, error) {
+	if len(jb.Result) == 0 {
+		return nil, errors.Errorf("job %s has no result, status: %s", jb.ID, jb.Status)
+	}
+
+	result := new(BulkCreateResult)
+	err := json.Unmarshal(jb.Result, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling job result")
+	}
+
+	return result, nil
+}
+
+// BulkCreateResult is the result of creating users in bulk
+type BulkCreateResult struct {
+	Accepted int
+	Inserted int
+	Failed   int
+	Errors   []RowError
+}
+
+// RowError is the error of a single user (row) in a bulk operation
+type RowError struct {
+	// Row is the index of the user in the list, starting with 0
+	Row   int
+	Email string
+	Error string
+}
+
+// ImportFormat is the format of the users being imported, and is sent as the Content-Type
+type ImportFormat string
+
+const (
+	// ImportCSV requires a header row, with the columns any of id, full_name, email, phone, contact_address
+	ImportCSV ImportFormat = "text/csv"
+	// ImportNDJSON has one user JSON per line
+	ImportNDJSON ImportFormat = "application/x-ndjson"
+)
+
+// ImportResult is the result of a single line of an import
+type ImportResult struct {
+	// Line is the line number in the imported body, starting with 1
+	Line  int
+	ID    string
+	Email string
+	// Status is one of inserted, duplicate, invalid
+	Status string
+	Error  string
+}
+
+// UserUpdate is used for partially updating a user, only the non-nil fields are updated
+type UserUpdate struct {
+	FullName       *string `json:",omitempty"`
+	Email          *string `json:",omitempty"`
+	Phone          *string `json:",omitempty"`
+	ContactAddress *string `json:",omitempty"`
 }
 
 type GoApp struct {
@@ -36,7 +166,7 @@ func (ht *GoApp) makeRequest(ctx context.Context, req *http.Request) ([]byte, er
 		return nil, errors.Wrap(err, "failed reading response body")
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		return nil, errors.Errorf("%d: %s", resp.StatusCode, string(raw))
 	}
 
@@ -79,7 +209,7 @@ func (ht *GoApp) UserByEmail(ctx context.Context, email string) (*User, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodGet,
-		fmt.Sprintf("%s/%s", ht.usersBase, email),
+		fmt.Sprintf("%s/email/%s", ht.usersBase, url.PathEscape(email)),
 		nil,
 	)
 	if err != nil {
@@ -102,6 +232,226 @@ func (ht *GoApp) UserByEmail(ctx context.Context, email string) (*User, error) {
 	return &respUsr.Data, nil
 }
 
+func (ht *GoApp) UserByID(ctx context.Context, userID string) (*User, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/%s", ht.usersBase, url.PathEscape(userID)),
+		nil,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing request")
+	}
+
+	raw, err := ht.makeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	respUsr := struct {
+		Data User `json:"data"`
+	}{}
+	err = json.Unmarshal(raw, &respUsr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling user")
+	}
+
+	return &respUsr.Data, nil
+}
+
+func (ht *GoApp) UpdateUser(ctx context.Context, userID string, uu *UserUpdate) (*User, error) {
+	payload, err := json.Marshal(uu)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling to json")
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPatch,
+		fmt.Sprintf("%s/%s", ht.usersBase, url.PathEscape(userID)),
+		bytes.NewBuffer(payload),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing request")
+	}
+
+	raw, err := ht.makeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	respUsr := struct {
+		Data User `json:"data"`
+	}{}
+	err = json.Unmarshal(raw, &respUsr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling user")
+	}
+
+	return &respUsr.Data, nil
+}
+
+func (ht *GoApp) DeleteUser(ctx context.Context, userID string) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodDelete,
+		fmt.Sprintf("%s/%s", ht.usersBase, url.PathEscape(userID)),
+		nil,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed preparing request")
+	}
+
+	_, err = ht.makeRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListUsers fetches a single page of users. cursor should be empty for the first page, and
+// the NextCursor of the previous page for the subsequent ones
+func (ht *GoApp) ListUsers(ctx context.Context, filter *UserFilter, cursor string) (*UserList, error) {
+	query := filter.query()
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s?%s", ht.usersBase, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing request")
+	}
+
+	raw, err := ht.makeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	respList := struct {
+		Data UserList `json:"data"`
+	}{}
+	err = json.Unmarshal(raw, &respList)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling users")
+	}
+
+	return &respList.Data, nil
+}
+
+// Users iterates over all the users matching the filter, fetching pages lazily.
+// Iteration stops at the first error, which is yielded along with a nil user
+func (ht *GoApp) Users(ctx context.Context, filter *UserFilter) iter.Seq2[*User, error] {
+	return func(yield func(*User, error) bool) {
+		cursor := ""
+		for {
+			list, err := ht.ListUsers(ctx, filter, cursor)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range list.Users {
+				if !yield(&list.Users[i], nil) {
+					return
+				}
+			}
+
+			if list.NextCursor == "" {
+				return
+			}
+			cursor = list.NextCursor
+		}
+	}
+}
+
+// Job returns the background job with the ID
+func (ht *GoApp) Job(ctx context.Context, jobID string) (*Job, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/jobs/%s", ht.basePath, url.PathEscape(jobID)),
+		nil,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed preparing request")
+	}
+
+	raw, err := ht.makeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	respJob := struct {
+		Data Job `json:"data"`
+	}{}
+	err = json.Unmarshal(raw, &respJob)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling job")
+	}
+
+	return &respJob.Data, nil
+}
+
+// Import creates users in bulk by streaming the body to the server, and yields the result
+// of every line as the server responds. Invalid or duplicate users do not stop the import.
+// Iteration stops at the first error, which is yielded along with a nil result
+func (ht *GoApp) Import(ctx context.Context, format ImportFormat, body io.Reader) iter.Seq2[*ImportResult, error] {
+	return func(yield func(*ImportResult, error) bool) {
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			ht.usersBase+"/import",
+			body,
+		)
+		if err != nil {
+			yield(nil, errors.Wrap(err, "failed preparing request"))
+			return
+		}
+		req.Header.Set("Content-Type", string(format))
+
+		resp, err := ht.client.Do(req)
+		if err != nil {
+			yield(nil, errors.Wrap(err, "failed making request"))
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			raw, _ := io.ReadAll(resp.Body)
+			yield(nil, errors.Errorf("%d: %s", resp.StatusCode, string(raw)))
+			return
+		}
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			result := new(ImportResult)
+			err = decoder.Decode(result)
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				yield(nil, errors.Wrap(err, "failed unmarshaling import result"))
+				return
+			}
+
+			// the import could not be completed if the server responds with a result without a line
+			if result.Line == 0 {
+				yield(nil, errors.Errorf("import failed: %s", result.Error))
+				return
+			}
+
+			if !yield(result, nil) {
+				return
+			}
+		}
+	}
+}
+
 func NewClient(basePath string) *GoApp {
 	return &GoApp{
 		client:    http.DefaultClient,
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
+	"sync/atomic"
 
 	"github.com/naughtygopher/errors"
 	"github.com/naughtygopher/proberesponder"
@@ -55,45 +55,62 @@ func recoverer() {
 func main() {
 	defer recoverer()
 	var (
-		ctx                 = context.Background()
-		fatalErr            = make(chan error, 1)
-		shutdownGraceperiod = time.Minute
-		probeInterval       = time.Second * 3
-		probestatus         = proberesponder.New()
+		ctx         = context.Background()
+		fatalErr    = make(chan error, 1)
+		probestatus = proberesponder.New()
 	)
 
-	cfgs, err := configs.New()
+	cfgs, err := configs.New(os.Args[1:]...)
 	if err != nil {
 		panic(errors.Wrap(err))
 	}
 
-	logger.UpdateDefaultLogger(logger.New(
-		cfgs.AppName, cfgs.AppVersion, 0,
-		map[string]string{
-			"env": cfgs.Environment.String(),
-		}),
-	)
+	logger.UpdateDefaultLogger(newLogger(cfgs))
+
+	if args := cfgs.Args(); len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			exitErr = runMigrateCommand(ctx, cfgs, args[1:])
+			return
+		case "export":
+			exitErr = runExportCommand(ctx, cfgs, args[1:])
+			return
+		case "backfill-emails":
+			exitErr = runBackfillEmailsCommand(ctx, cfgs, args[1:])
+			return
+		}
+	}
+
+	activeCfgs := &atomic.Pointer[configs.Configs]{}
+	activeCfgs.Store(cfgs)
+	handleSignals(ctx, activeCfgs, fatalErr)
 
-	healthResponder, err := startHealthResponder(ctx, probestatus, fatalErr)
+	healthResponder, err := startHealthResponder(ctx, probestatus, activeCfgs, fatalErr)
 	if err != nil {
 		panic(err)
 	}
 
-	hserver, gserver := start(ctx, probestatus, cfgs, fatalErr)
+	lc, err := start(ctx, probestatus, cfgs, fatalErr)
+	defer func() {
+		// the active configs are read only at shutdown, since they could've been reloaded
+		current := activeCfgs.Load()
+		shutdown(
+			current.ShutdownGracePeriod(),
+			current.ProbeInterval(),
+			probestatus,
+			healthResponder,
+			lc,
+		)
+	}()
+	if err != nil {
+		exitErr = err
+		return
+	}
 
 	// by now all the intended servers, subscribers etc. are up and running.
 	probestatus.SetNotStarted(false)
 	probestatus.SetNotReady(false)
 	probestatus.SetNotLive(false)
 
-	defer shutdown(
-		shutdownGraceperiod,
-		probeInterval,
-		probestatus,
-		healthResponder,
-		hserver,
-		gserver,
-		startAPM(ctx, cfgs),
-	)
 	exitErr = <-fatalErr
 }
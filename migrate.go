@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/configs"
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/pkg/migrations"
+	"github.com/naughtygopher/goapp/internal/pkg/postgres"
+	"github.com/naughtygopher/goapp/schemas"
+)
+
+const migrateUsage = "usage: migrate up|down [steps]|status"
+
+func newMigrator(pqdriver *pgxpool.Pool) (*migrations.Migrator, error) {
+	return migrations.New(pqdriver, schemas.Migrations(), migrations.DefaultTableName)
+}
+
+// migrateUp applies all the pending migrations
+func migrateUp(ctx context.Context, pqdriver *pgxpool.Pool) error {
+	migrator, err := newMigrator(pqdriver)
+	if err != nil {
+		return err
+	}
+
+	return applyMigrations(ctx, migrator)
+}
+
+// applyMigrations applies all the pending migrations using migrator, and logs every migration applied
+func applyMigrations(ctx context.Context, migrator *migrations.Migrator) error {
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		logger.Info(ctx, fmt.Sprintf("[migrations] applied %d_%s", m.Version, m.Name))
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigrateCommand is the 'migrate' subcommand of the app, args are the arguments after 'migrate'
+func runMigrateCommand(ctx context.Context, cfgs *configs.Configs, args []string) error {
+	if len(args) == 0 {
+		return errors.Validation(migrateUsage)
+	}
+
+	pqdriver, err := postgres.NewPool(cfgs.Postgres())
+	if err != nil {
+		return err
+	}
+	defer pqdriver.Close()
+
+	migrator, err := newMigrator(pqdriver)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		return applyMigrations(ctx, migrator)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return errors.ValidationErr(err, migrateUsage)
+			}
+		}
+
+		rolledback, err := migrator.Down(ctx, steps)
+		for _, m := range rolledback {
+			logger.Info(ctx, fmt.Sprintf("[migrations] rolled back %d_%s", m.Version, m.Name))
+		}
+		return err
+
+	case "status":
+		list, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, st := range list {
+			status := "pending"
+			if st.Unknown {
+				status = "applied, no migration file"
+			} else if !st.AppliedAt.IsZero() {
+				status = fmt.Sprintf("applied at %s", st.AppliedAt)
+			}
+			fmt.Printf("%d_%s: %s\n", st.Version, st.Name, status)
+		}
+		return nil
+	}
+
+	return errors.Validation(migrateUsage)
+}
@@ -8,5 +8,5 @@ CREATE TABLE IF NOT EXISTS users (
     updated_at timestamptz DEFAULT now()
 );
 
-CREATE TRIGGER tr_users_bu BEFORE UPDATE on users
+CREATE OR REPLACE TRIGGER tr_users_bu BEFORE UPDATE on users
   FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS user_notes_user_id_updated_at_idx;
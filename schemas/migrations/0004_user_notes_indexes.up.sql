@@ -0,0 +1,2 @@
+-- supports listing a user's notes with keyset pagination on (updated_at, id)
+CREATE INDEX IF NOT EXISTS user_notes_user_id_updated_at_idx ON user_notes (user_id, updated_at, id);
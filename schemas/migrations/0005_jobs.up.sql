@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    -- run_at is the time after which a queued job is due, and for a running job the time until
+    -- which it's leased to the worker
+    run_at timestamptz NOT NULL DEFAULT now(),
+    last_error TEXT,
+    result JSONB,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS jobs_due_idx ON jobs (run_at, created_at)
+    WHERE status IN ('queued', 'running');
+
+CREATE OR REPLACE TRIGGER tr_jobs_bu BEFORE UPDATE on jobs
+  FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    -- fingerprint is the hash of the request, so that a key reused for a different request is rejected
+    fingerprint TEXT NOT NULL,
+    -- the response is NULL while the request is in progress
+    response_status INT,
+    response_headers JSONB,
+    response_body BYTEA,
+    -- expires_at is the time until which an in progress request holds the key, and
+    -- for a completed request the time until which the response is stored
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+CREATE OR REPLACE TRIGGER tr_idempotency_keys_bu BEFORE UPDATE on idempotency_keys
+  FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- emails are unique irrespective of case. This fails if there are existing emails which differ only
+-- in case, run the 'backfill-emails' command to find & resolve such collisions before migrating
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email));
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
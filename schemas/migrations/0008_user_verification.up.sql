@@ -0,0 +1,15 @@
+-- existing users are considered verified, only the users created from now on have to verify their email
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'verified';
+ALTER TABLE users ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE TABLE IF NOT EXISTS user_verification_tokens (
+    -- token_hash is the SHA-256 of the token, the token itself is never stored
+    token_hash TEXT PRIMARY KEY,
+    user_id UUID NOT NULL references users(id) ON DELETE CASCADE,
+    -- email is the email the token was sent to, a token is invalid once the email is changed
+    email TEXT NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_verification_tokens_user_id_idx ON user_verification_tokens (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE user_notes DROP CONSTRAINT IF EXISTS user_notes_user_id_fkey;
+ALTER TABLE user_notes ADD CONSTRAINT user_notes_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id);
@@ -0,0 +1,5 @@
+-- databases created before the migrations already had user_notes, without ON DELETE CASCADE,
+-- which 0003_user_notes skips being 'IF NOT EXISTS'. So the foreign key is recreated with it
+ALTER TABLE user_notes DROP CONSTRAINT IF EXISTS user_notes_user_id_fkey;
+ALTER TABLE user_notes ADD CONSTRAINT user_notes_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
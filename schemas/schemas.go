@@ -0,0 +1,17 @@
+// Package schemas embeds all the SQL migrations of the application, so that the binary can
+// apply them without depending on files being available at runtime
+package schemas
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the versioned migrations, with the migration files at the root
+func Migrations() fs.FS {
+	sub, _ := fs.Sub(migrations, "migrations")
+	return sub
+}
@@ -4,12 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
-	"github.com/naughtygopher/goapp/cmd/server/grpc"
-	xhttp "github.com/naughtygopher/goapp/cmd/server/http"
-	"github.com/naughtygopher/goapp/internal/pkg/apm"
+	"github.com/naughtygopher/errors"
+	"github.com/naughtygopher/goapp/internal/pkg/lifecycle"
 	"github.com/naughtygopher/goapp/internal/pkg/logger"
 	"github.com/naughtygopher/proberesponder"
 )
@@ -19,9 +17,7 @@ func shutdown(
 	probeInterval time.Duration,
 	pResp *proberesponder.ProbeResponder,
 	healthResp *http.Server,
-	httpServer *xhttp.HTTP,
-	grpcServer *grpc.GRPC,
-	apmIns *apm.APM,
+	lc *lifecycle.Manager,
 ) {
 	// set the service as Not ready as soon as it's exiting main
 	pResp.SetNotReady(true)
@@ -60,42 +56,20 @@ func shutdown(
 		fmt.Sprintf("initiated: %s", time.Now().Format(time.RFC3339)),
 	)
 	logger.Info(ctx, "initiating shutdown")
-	shutdownDependenciesAndServices(ctx, httpServer, grpcServer, apmIns)
-}
-
-func shutdownDependenciesAndServices(
-	ctx context.Context,
-	httpServer *xhttp.HTTP,
-	grpcServer *grpc.GRPC,
-	apmIns *apm.APM,
-) {
-	wgroup := &sync.WaitGroup{}
-	if httpServer != nil {
-		wgroup.Add(1)
-		go func() {
-			defer wgroup.Done()
-			_ = httpServer.Shutdown(ctx)
-		}()
-	}
 
-	if grpcServer != nil {
-		wgroup.Add(1)
-		go func() {
-			defer wgroup.Done()
-			_ = grpcServer.Shutdown(ctx)
-		}()
+	// the lifecycle manager stops the APIs of the application (e.g. HTTP, gRPC, Pubsub listener etc.)
+	// before the dependencies like database, cache, APM etc. which they depend on
+	for _, result := range lc.Stop(ctx) {
+		status := fmt.Sprintf("stopped in %s", result.Duration)
+		if errors.Is(result.Err, lifecycle.ErrStopSkipped) {
+			status = fmt.Sprintf("skipped: %s", result.Err.Error())
+			logger.Warn(ctx, fmt.Sprintf("[%s] %s", result.Name, status))
+		} else if result.Err != nil {
+			status = fmt.Sprintf("failed after %s: %s", result.Duration, result.Err.Error())
+			logger.Error(ctx, fmt.Sprintf("[%s] %s", result.Name, status), result.Err)
+		} else {
+			logger.Info(ctx, fmt.Sprintf("[%s] %s", result.Name, status))
+		}
+		pResp.AppendHealthResponse(fmt.Sprintf("shutdown.%s", result.Name), status)
 	}
-
-	// after all the APIs of the application are shutdown (e.g. HTTP, gRPC, Pubsub listener etc.)
-	// we should close connections to dependencies like database, cache etc.
-	// This should only be done after the APIs are shutdown completely
-	if apmIns != nil {
-		wgroup.Add(1)
-		go func() {
-			defer wgroup.Done()
-			_ = apmIns.Shutdown(ctx)
-		}()
-	}
-
-	wgroup.Wait()
 }
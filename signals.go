@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/naughtygopher/errors"
+
+	"github.com/naughtygopher/goapp/internal/configs"
+	"github.com/naughtygopher/goapp/internal/pkg/logger"
+	"github.com/naughtygopher/goapp/internal/pkg/sysignals"
+)
+
+// exitCodeForced is the exit code when the app is forced to quit during graceful shutdown
+const exitCodeForced = 4
+
+// handleSignals wires the OS signals to the app.
+//   - Quit signals (SIGINT, SIGTERM etc.) push an error to fatalErr, which initiates the graceful shutdown.
+//     A repeated quit signal during the shutdown, forces the app to exit immediately
+//   - SIGHUP reloads the configuration
+//   - SIGUSR1 dumps the goroutines & runtime stats to the logs
+func handleSignals(ctx context.Context, cfgs *atomic.Pointer[configs.Configs], fatalErr chan<- error) {
+	go sysignals.NotifyErrorOnQuitWithForce(fatalErr, func(sig os.Signal) {
+		logger.Error(ctx, fmt.Sprintf("received %v during graceful shutdown, forcing exit", sig))
+		os.Exit(exitCodeForced)
+	})
+
+	go sysignals.Notify(ctx, func(sig os.Signal) {
+		switch sig {
+		case syscall.SIGHUP:
+			reloadConfigs(ctx, cfgs)
+		case syscall.SIGUSR1:
+			dumpRuntimeStats(ctx)
+		}
+	}, syscall.SIGHUP, syscall.SIGUSR1)
+}
+
+// reloadConfigs loads the configuration again, and replaces the active one only if it's valid.
+// Only the configurations read at runtime (e.g. lifecycle timings, logger) take effect without a restart
+func reloadConfigs(ctx context.Context, cfgs *atomic.Pointer[configs.Configs]) {
+	next, err := configs.New(os.Args[1:]...)
+	if err != nil {
+		logger.Error(ctx, errors.Wrap(err, "config reload failed, retaining the active configuration"))
+		return
+	}
+
+	restartRequired := make([]string, 0)
+	current := cfgs.Load()
+	if current.Environment != next.Environment {
+		restartRequired = append(restartRequired, "environment")
+	}
+	if current.StoreType != next.StoreType {
+		restartRequired = append(restartRequired, "store")
+	}
+	if current.HTTPServer != next.HTTPServer {
+		restartRequired = append(restartRequired, "http")
+	}
+	if current.GRPCServer != next.GRPCServer {
+		restartRequired = append(restartRequired, "grpc")
+	}
+	if current.PostgresDB != next.PostgresDB {
+		restartRequired = append(restartRequired, "postgres")
+	}
+	if current.APMOpts != next.APMOpts {
+		restartRequired = append(restartRequired, "apm")
+	}
+	if current.JobsOpts != next.JobsOpts {
+		restartRequired = append(restartRequired, "jobs")
+	}
+	if current.IdempotencyOpts != next.IdempotencyOpts {
+		restartRequired = append(restartRequired, "idempotency")
+	}
+	if current.MailerOpts != next.MailerOpts {
+		restartRequired = append(restartRequired, "mailer")
+	}
+	if current.VerificationOpts != next.VerificationOpts {
+		restartRequired = append(restartRequired, "verification")
+	}
+	if current.HealthPort() != next.HealthPort() {
+		restartRequired = append(restartRequired, "lifecycle.healthPort")
+	}
+	if len(restartRequired) > 0 {
+		logger.Warn(ctx, fmt.Sprintf(
+			"config reload: changes to [%s] require a restart to take effect",
+			strings.Join(restartRequired, ", "),
+		))
+	}
+
+	cfgs.Store(next)
+	logger.UpdateDefaultLogger(newLogger(next))
+	logger.Info(ctx, "config reloaded")
+}
+
+func dumpRuntimeStats(ctx context.Context) {
+	mstats := runtime.MemStats{}
+	runtime.ReadMemStats(&mstats)
+	logger.Info(ctx, fmt.Sprintf(
+		"runtime stats: goroutines=%d heapAlloc=%d heapInuse=%d sys=%d numGC=%d gcPauseTotal=%s",
+		runtime.NumGoroutine(),
+		mstats.HeapAlloc,
+		mstats.HeapInuse,
+		mstats.Sys,
+		mstats.NumGC,
+		time.Duration(mstats.PauseTotalNs),
+	))
+
+	buff := bytes.NewBuffer(nil)
+	err := pprof.Lookup("goroutine").WriteTo(buff, 1)
+	if err != nil {
+		logger.Error(ctx, errors.Wrap(err, "failed dumping goroutines"))
+		return
+	}
+	logger.Info(ctx, "goroutine dump", buff.String())
+}